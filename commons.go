@@ -88,9 +88,15 @@ const (
 	EXIF_TAG_ISO_SPEED_RATINGS                        Tag = 0x8827
 	EXIF_TAG_OECF                                     Tag = 0x8828
 	EXIF_TAG_TIME_ZONE_OFFSET                         Tag = 0x882a
+	EXIF_TAG_SENSITIVITY_TYPE                         Tag = 0x8830
+	EXIF_TAG_STANDARD_OUTPUT_SENSITIVITY              Tag = 0x8831
+	EXIF_TAG_RECOMMENDED_EXPOSURE_INDEX               Tag = 0x8832
 	EXIF_TAG_EXIF_VERSION                             Tag = 0x9000
 	EXIF_TAG_DATE_TIME_ORIGINAL                       Tag = 0x9003
 	EXIF_TAG_DATE_TIME_DIGITIZED                      Tag = 0x9004
+	EXIF_TAG_OFFSET_TIME                              Tag = 0x9010
+	EXIF_TAG_OFFSET_TIME_ORIGINAL                     Tag = 0x9011
+	EXIF_TAG_OFFSET_TIME_DIGITIZED                    Tag = 0x9012
 	EXIF_TAG_COMPONENTS_CONFIGURATION                 Tag = 0x9101
 	EXIF_TAG_COMPRESSED_BITS_PER_PIXEL                Tag = 0x9102
 	EXIF_TAG_SHUTTER_SPEED_VALUE                      Tag = 0x9201
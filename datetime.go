@@ -0,0 +1,192 @@
+package exif
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateTimeLayout is the layout of the EXIF date and time tags.
+const DateTimeLayout = "2006:01:02 15:04:05"
+
+var (
+	ErrInvalidOffset = errors.New("invalid time offset")
+)
+
+// DateTime is a point in time read from the EXIF date tags.
+type DateTime struct {
+	Time time.Time
+	// ZoneKnown reports whether the file records the UTC offset of Time.
+	// When it does not, Time is assumed to be in UTC.
+	ZoneKnown bool
+	// Tag is the tag Time was read from.
+	Tag Tag
+}
+
+type dateTimeSource struct {
+	ifd    Ifd
+	tag    Tag
+	subSec Tag
+	offset Tag
+	// zone is the index of the matching EXIF_TAG_TIME_ZONE_OFFSET value.
+	zone int
+}
+
+// dateTimeSources lists the date tags by order of preference.
+var dateTimeSources = []dateTimeSource{
+	{IfdExif, EXIF_TAG_DATE_TIME_ORIGINAL, EXIF_TAG_SUB_SEC_TIME_ORIGINAL, EXIF_TAG_OFFSET_TIME_ORIGINAL, 1},
+	{IfdExif, EXIF_TAG_DATE_TIME_DIGITIZED, EXIF_TAG_SUB_SEC_TIME_DIGITIZED, EXIF_TAG_OFFSET_TIME_DIGITIZED, 1},
+	{Ifd0, EXIF_TAG_DATE_TIME, EXIF_TAG_SUB_SEC_TIME, EXIF_TAG_OFFSET_TIME, 0},
+}
+
+// GetTimestamp returns the time the image was taken. See GetDateTime.
+func (h *Helper) GetTimestamp() (*time.Time, error) {
+	dt, err := h.GetDateTime()
+	if err != nil {
+		return nil, err
+	}
+
+	return &dt.Time, nil
+}
+
+// GetDateTime returns the time the image was taken, read from
+// DateTimeOriginal, DateTimeDigitized or DateTime, in that order. Sub-second
+// precision and the UTC offset are taken from the matching SubSecTime* and
+// OffsetTime* tags, or from TimeZoneOffset.
+func (h *Helper) GetDateTime() (*DateTime, error) {
+	var first, missing error
+	for _, src := range dateTimeSources {
+		dt, err := h.getDateTime(src)
+		if err == nil {
+			return dt, nil
+		}
+		if !errors.Is(err, ErrNotFoundEntry) {
+			if first == nil {
+				first = err
+			}
+		} else if missing == nil {
+			missing = err
+		}
+	}
+
+	if first != nil {
+		return nil, first
+	}
+	return nil, missing
+}
+
+func (h *Helper) getDateTime(src dateTimeSource) (*DateTime, error) {
+	s, err := h.GetString(src.ifd, src.tag)
+	if err != nil {
+		return nil, err
+	}
+	// Unknown dates are recorded as blanks or zeros.
+	if strings.Trim(s, " :0") == "" {
+		return nil, &EntryError{Ifd: src.ifd, Tag: src.tag, Err: ErrNotFoundEntry}
+	}
+
+	t, err := time.ParseInLocation(DateTimeLayout, s, time.UTC)
+	if err != nil {
+		return nil, err
+	}
+
+	if sub, err := h.GetString(IfdExif, src.subSec); err == nil {
+		t = t.Add(parseSubSec(sub))
+	}
+
+	loc, known := h.getLocation(src)
+	if known {
+		t = time.Date(t.Year(), t.Month(), t.Day(),
+			t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	}
+
+	return &DateTime{
+		Time:      t,
+		ZoneKnown: known,
+		Tag:       src.tag,
+	}, nil
+}
+
+// getLocation returns the zone of the date tag described by src.
+func (h *Helper) getLocation(src dateTimeSource) (*time.Location, bool) {
+	if s, err := h.GetString(IfdExif, src.offset); err == nil {
+		if offset, err := ParseOffset(s); err == nil {
+			return time.FixedZone(s, offset), true
+		}
+	}
+
+	for _, ifd := range []Ifd{Ifd0, IfdExif} {
+		v, err := h.GetValue(ifd, EXIF_TAG_TIME_ZONE_OFFSET)
+		if err != nil {
+			continue
+		}
+		hours, ok := v.([]int16)
+		if !ok || len(hours) == 0 {
+			continue
+		}
+		hour := hours[0]
+		if src.zone < len(hours) {
+			hour = hours[src.zone]
+		}
+		if hour < -12 || hour > 14 {
+			continue
+		}
+		return time.FixedZone("", int(hour)*3600), true
+	}
+
+	return time.UTC, false
+}
+
+// ParseOffset parses an OffsetTime value such as "+09:00" and returns the
+// offset in seconds east of UTC.
+func ParseOffset(s string) (int, error) {
+	if len(s) != 6 || s[3] != ':' {
+		return 0, ErrInvalidOffset
+	}
+
+	sign := 1
+	switch s[0] {
+	case '+':
+	case '-':
+		sign = -1
+	default:
+		return 0, ErrInvalidOffset
+	}
+
+	for _, c := range s[1:3] + s[4:6] {
+		if c < '0' || c > '9' {
+			return 0, ErrInvalidOffset
+		}
+	}
+	hours, _ := strconv.Atoi(s[1:3])
+	minutes, _ := strconv.Atoi(s[4:6])
+	if minutes >= 60 {
+		return 0, ErrInvalidOffset
+	}
+
+	// Time zones range from -12:00 to +14:00.
+	offset := sign * (hours*3600 + minutes*60)
+	if offset < -12*3600 || offset > 14*3600 {
+		return 0, ErrInvalidOffset
+	}
+	return offset, nil
+}
+
+// parseSubSec converts the digits of a SubSecTime value into a duration.
+// "5" is half a second, "123" 123 milliseconds.
+func parseSubSec(s string) time.Duration {
+	s = strings.TrimSpace(s)
+	if len(s) > 9 {
+		s = s[:9]
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0
+	}
+	for i := len(s); i < 9; i++ {
+		n *= 10
+	}
+	return time.Duration(n)
+}
@@ -0,0 +1,117 @@
+package exif
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Error messages.
+var (
+	ErrCorruptData = errors.New(`corrupt exif data`)
+)
+
+// LogCode tells how serious a Diagnostic is, as ExifLogCode does in libexif.
+type LogCode int
+
+// Diagnostic codes.
+const (
+	LogNone LogCode = iota
+	LogDebug
+	LogNoMemory
+	LogCorruptData
+)
+
+func (c LogCode) String() string {
+	switch c {
+	case LogNone:
+		return "none"
+	case LogDebug:
+		return "debug"
+	case LogNoMemory:
+		return "no memory"
+	case LogCorruptData:
+		return "corrupt data"
+	}
+	return fmt.Sprintf("LogCode(%d)", int(c))
+}
+
+// Diagnostic is a message reported while reading the data, such as an entry
+// pointing out of the block, or a value fixed to follow the specification.
+type Diagnostic struct {
+	Code LogCode
+	// Domain is the part of the parser reporting the message, such as
+	// "ExifData".
+	Domain  string
+	Message string
+
+	// Ifd and Tag are the IFD and the entry the message is about, when
+	// HasIfd and HasTag are set.
+	Ifd    Ifd
+	Tag    Tag
+	HasIfd bool
+	HasTag bool
+}
+
+func (g *Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s", g.Domain, g.Code, g.Message)
+}
+
+// libexifIfdNames maps the names libexif gives the IFDs in its messages.
+var libexifIfdNames = map[string]Ifd{
+	"0":                Ifd0,
+	"1":                Ifd1,
+	"EXIF":             IfdExif,
+	"GPS":              IfdGps,
+	"Interoperability": IfdInterOperability,
+}
+
+var (
+	logQuoted = regexp.MustCompile(`'([^']*)'`)
+	logTag    = regexp.MustCompile(`(?i)(?:tag|entry) '?(0x[0-9a-f]+|[a-z0-9]+)'?`)
+)
+
+// locate sets the IFD and the tag from the message, when it names them. The
+// IFD is then named as Ifd.String does, as in the messages of the Go reader.
+func (g *Diagnostic) locate() {
+	for _, m := range logQuoted.FindAllStringSubmatchIndex(g.Message, -1) {
+		if ifd, ok := libexifIfdNames[g.Message[m[2]:m[3]]]; ok {
+			g.Ifd, g.HasIfd = ifd, true
+			g.Message = g.Message[:m[2]] + ifd.String() + g.Message[m[3]:]
+			break
+		}
+	}
+
+	for _, m := range logTag.FindAllStringSubmatch(g.Message, -1) {
+		if strings.HasPrefix(m[1], "0x") {
+			if v, err := strconv.ParseUint(m[1][2:], 16, 16); err == nil {
+				g.Tag, g.HasTag = Tag(v), true
+				return
+			}
+			continue
+		}
+		if tag, ok := TagFromName(m[1]); ok {
+			g.Tag, g.HasTag = tag, true
+			return
+		}
+	}
+}
+
+// strictError returns the first error reported while reading, when
+// Options.Strict is set.
+func (d *Data) strictError() error {
+	if !d.Options.Strict {
+		return nil
+	}
+	for i := range d.Diagnostics {
+		switch d.Diagnostics[i].Code {
+		case LogNoMemory:
+			return fmt.Errorf("%w: %s", ErrNoMemory, d.Diagnostics[i].Message)
+		case LogCorruptData:
+			return fmt.Errorf("%w: %s", ErrCorruptData, d.Diagnostics[i].Message)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package exif
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostics(t *testing.T) {
+	// The Make value and the EXIF IFD are out of bounds, Orientation has the
+	// wrong format.
+	src := newTiffBytes(binary.LittleEndian).u16(3).
+		entry(EXIF_TAG_MAKE, FormatAscii, 20, 0x1000).
+		entry(EXIF_TAG_ORIENTATION, FormatAscii, 2, 0x41).
+		entry(EXIF_TAG_EXIF_IFD_POINTER, FormatUnsignedLong, 1, 0x2000).u32(0).jpeg()
+
+	data := parseWith(t, ReadOptions{}, src)
+	var corrupt []Diagnostic
+	for _, g := range data.Diagnostics {
+		if g.Code == LogCorruptData {
+			corrupt = append(corrupt, g)
+		}
+	}
+	require.NotEmpty(t, corrupt)
+
+	located := false
+	for _, g := range corrupt {
+		if g.HasTag && (g.Tag == EXIF_TAG_ORIENTATION || g.Tag == EXIF_TAG_EXIF_IFD_POINTER) {
+			located = true
+		}
+	}
+	assert.True(t, located)
+
+	data = New()
+	data.Options.Strict = true
+	_, err := data.Write(src)
+	require.Equal(t, ErrFoundExifInData, err)
+	err = data.Parse()
+	assert.True(t, errors.Is(err, ErrCorruptData), err)
+	assert.NotEmpty(t, data.Diagnostics)
+
+	_, err = ReadWithOptions("_examples/resources/test.jpg", ReadOptions{Strict: true})
+	assert.NoError(t, err)
+}
+
+func TestDiagnosticLocate(t *testing.T) {
+	g := Diagnostic{Message: "Tag 'ExifVersion' is mandatory in IFD 'EXIF' and has therefore been added."}
+	g.locate()
+	assert.Equal(t, Diagnostic{
+		Message: g.Message,
+		Ifd:     IfdExif,
+		Tag:     EXIF_TAG_EXIF_VERSION,
+		HasIfd:  true,
+		HasTag:  true,
+	}, g)
+
+	g = Diagnostic{Message: "Unknown tag 0x9999 (entry 1 in 'GPS')."}
+	g.locate()
+	assert.Equal(t, IfdGps, g.Ifd)
+	assert.Equal(t, Tag(0x9999), g.Tag)
+
+	g = Diagnostic{Message: "Unknown tag 0x9999 (entry 1 in '0')."}
+	g.locate()
+	assert.Equal(t, "Unknown tag 0x9999 (entry 1 in 'IFD0').", g.Message)
+	assert.Equal(t, Ifd0, g.Ifd)
+
+	g = Diagnostic{Message: "Tag data past end of buffer (12 > 52)"}
+	g.locate()
+	assert.False(t, g.HasIfd)
+	assert.False(t, g.HasTag)
+}
+
+func TestDiagnosticParity(t *testing.T) {
+	// Both readers report the unknown tags and the IFDs loaded twice in the
+	// same words.
+	src := newTiffBytes(binary.LittleEndian).u16(3).
+		short(EXIF_TAG_ORIENTATION, 1).
+		short(0x9999, 7).
+		entry(EXIF_TAG_EXIF_IFD_POINTER, FormatUnsignedLong, 1, 50).u32(0).
+		u16(2).
+		short(0x9998, 1).
+		entry(EXIF_TAG_INTEROPERABILITY_IFD_POINTER, FormatUnsignedLong, 1, 50).u32(0).jpeg()
+
+	want := []Diagnostic{
+		{LogDebug, "ExifData", "Unknown tag 0x9999 (entry 1 in 'IFD0').", Ifd0, 0x9999, true, true},
+		{LogDebug, "ExifData", "Unknown tag 0x9998 (entry 0 in 'EXIF').", IfdExif, 0x9998, true, true},
+		{LogDebug, "ExifData", "Unknown tag 0x9998 (entry 0 in 'Interoperability').", IfdInterOperability, 0x9998, true, true},
+		{LogDebug, "ExifData", "Recursive entry in IFD 'Interoperability' detected. Skipping...", IfdInterOperability, 0, true, false},
+	}
+
+	// libexif logs more steps, and asks for the unknown tags to be reported.
+	data := parseWith(t, ReadOptions{NoFix: true}, src)
+	var got []Diagnostic
+	for _, g := range data.Diagnostics {
+		if !g.HasIfd {
+			continue
+		}
+		if i := len(got); i < len(want) && strings.HasPrefix(g.Message, want[i].Message) {
+			g.Message = want[i].Message
+		}
+		got = append(got, g)
+	}
+	assert.Equal(t, want, got)
+}
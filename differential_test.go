@@ -0,0 +1,264 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tiffBytes builds TIFF data by hand, to describe malformed files.
+type tiffBytes struct {
+	order binary.ByteOrder
+	b     []byte
+}
+
+func newTiffBytes(order binary.ByteOrder) *tiffBytes {
+	t := &tiffBytes{order: order}
+	if order == binary.LittleEndian {
+		t.b = []byte("II*\x00")
+	} else {
+		t.b = []byte("MM\x00*")
+	}
+	return t.u32(8)
+}
+
+func (t *tiffBytes) u16(v uint16) *tiffBytes {
+	t.b = append(t.b, 0, 0)
+	t.order.PutUint16(t.b[len(t.b)-2:], v)
+	return t
+}
+
+func (t *tiffBytes) u32(v uint32) *tiffBytes {
+	t.b = append(t.b, 0, 0, 0, 0)
+	t.order.PutUint32(t.b[len(t.b)-4:], v)
+	return t
+}
+
+// entry appends an IFD entry whose value field holds value.
+func (t *tiffBytes) entry(tag Tag, format EntryFormat, count, value uint32) *tiffBytes {
+	return t.u16(uint16(tag)).u16(uint16(format)).u32(count).u32(value)
+}
+
+// short appends an entry holding a single SHORT.
+func (t *tiffBytes) short(tag Tag, v uint16) *tiffBytes {
+	t.u16(uint16(tag)).u16(uint16(FormatUnsignedShort)).u32(1).u16(v)
+	t.b = append(t.b, 0, 0)
+	return t
+}
+
+func (t *tiffBytes) bytes(b ...byte) *tiffBytes {
+	t.b = append(t.b, b...)
+	return t
+}
+
+// jpeg wraps the TIFF data in the Exif segment of an empty JPEG file.
+func (t *tiffBytes) jpeg() []byte {
+	payload := append([]byte("Exif\x00\x00"), t.b...)
+	out := []byte{0xff, 0xd8, 0xff, 0xe1, 0, 0}
+	binary.BigEndian.PutUint16(out[4:], uint16(len(payload)+2))
+	out = append(out, payload...)
+	return append(out, 0xff, 0xd9)
+}
+
+// malformedFiles are synthetic files covering the corner cases of the
+// format.
+var malformedFiles = map[string]func() []byte{
+	"big endian": func() []byte {
+		return newTiffBytes(binary.BigEndian).u16(2).
+			short(EXIF_TAG_ORIENTATION, 6).
+			entry(EXIF_TAG_MAKE, FormatAscii, 5, 38).u32(0).
+			bytes([]byte("ACME\x00")...).jpeg()
+	},
+	"truncated ifd": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(5).
+			short(EXIF_TAG_ORIENTATION, 3).
+			short(EXIF_TAG_RESOLUTION_UNIT, 2).jpeg()
+	},
+	"value out of bounds": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(2).
+			entry(EXIF_TAG_MAKE, FormatAscii, 100, 0x1000).
+			short(EXIF_TAG_ORIENTATION, 1).u32(0).jpeg()
+	},
+	"duplicate tag": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(2).
+			short(EXIF_TAG_ORIENTATION, 1).
+			short(EXIF_TAG_ORIENTATION, 8).u32(0).jpeg()
+	},
+	"sub ifds": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(2).
+			short(EXIF_TAG_ORIENTATION, 1).
+			entry(EXIF_TAG_SUB_IFDS, FormatUnsignedLong, 1, 38).u32(56).
+			u16(1).short(EXIF_TAG_IMAGE_WIDTH, 4000).u32(0).
+			u16(1).short(EXIF_TAG_COMPRESSION, 6).u32(74).
+			u16(1).short(EXIF_TAG_IMAGE_WIDTH, 10).u32(56).jpeg()
+	},
+	"private ifd": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(2).
+			short(EXIF_TAG_ORIENTATION, 1).
+			entry(0xc000, 13, 1, 38).u32(0).
+			u16(1).short(EXIF_TAG_IMAGE_WIDTH, 4000).u32(56).
+			u16(1).short(EXIF_TAG_IMAGE_LENGTH, 3000).u32(0).jpeg()
+	},
+	"exif pointer loop": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(2).
+			short(EXIF_TAG_ORIENTATION, 1).
+			entry(EXIF_TAG_EXIF_IFD_POINTER, FormatUnsignedLong, 1, 8).u32(0).jpeg()
+	},
+	"ifd1 loop": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(1).
+			short(EXIF_TAG_ORIENTATION, 1).u32(8).jpeg()
+	},
+	"ifd1 out of bounds": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(1).
+			short(EXIF_TAG_ORIENTATION, 1).u32(0xfff0).jpeg()
+	},
+	"unknown format": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(2).
+			entry(EXIF_TAG_ORIENTATION, 13, 1, 1).
+			short(EXIF_TAG_RESOLUTION_UNIT, 2).u32(0).jpeg()
+	},
+	"zero components": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(2).
+			entry(EXIF_TAG_MAKE, FormatAscii, 0, 0).
+			short(EXIF_TAG_ORIENTATION, 1).u32(0).jpeg()
+	},
+	"huge count": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(2).
+			entry(EXIF_TAG_MAKE, FormatUnsignedRational, 0x20000001, 8).
+			short(EXIF_TAG_ORIENTATION, 1).u32(0).jpeg()
+	},
+	"unknown tag": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(2).
+			short(EXIF_TAG_ORIENTATION, 1).
+			short(0xc000, 7).u32(0).jpeg()
+	},
+	"tag in the wrong ifd": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(2).
+			short(EXIF_TAG_ORIENTATION, 1).
+			entry(EXIF_TAG_GPS_LATITUDE_REF, FormatAscii, 2, 'N').u32(0).jpeg()
+	},
+	"empty entry": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(2).
+			entry(0, 0, 0, 0).
+			short(EXIF_TAG_ORIENTATION, 1).u32(0).jpeg()
+	},
+	"wrong format": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(2).
+			entry(EXIF_TAG_ORIENTATION, FormatUnsignedLong, 1, 6).
+			entry(EXIF_TAG_RESOLUTION_UNIT, FormatSignedShort, 1, 2).u32(0).jpeg()
+	},
+	"ascii without nul": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(1).
+			entry(EXIF_TAG_MAKE, FormatAscii, 4, binary.LittleEndian.Uint32([]byte("ACME"))).u32(0).jpeg()
+	},
+	"exif ifd": func() []byte {
+		return newTiffBytes(binary.LittleEndian).u16(1).
+			entry(EXIF_TAG_EXIF_IFD_POINTER, FormatUnsignedLong, 1, 26).u32(0).
+			u16(2).
+			entry(EXIF_TAG_EXPOSURE_TIME, FormatUnsignedRational, 1, 56).
+			entry(EXIF_TAG_INTEROPERABILITY_IFD_POINTER, FormatUnsignedLong, 1, 26).u32(0).
+			u32(1).u32(125).jpeg()
+	},
+}
+
+// checkDifferential reads file and compares Data.Raw with the reference
+// walker, failing on the differences nothing explains.
+func checkDifferential(t *testing.T, name string, file []byte) {
+	// The block is found without the parser, which could cut it wrong.
+	block := refExifBlock(file)
+	if bytes.HasPrefix(file, exifHeader) {
+		block = file
+	}
+
+	// libexif only looks at the first 64K of a block.
+	walked := block
+	if len(walked) > 0xfffe {
+		walked = walked[:0xfffe]
+	}
+	var ref *refFile
+	refErr := fmt.Errorf("no exif block")
+	if block != nil {
+		ref, refErr = refWalk(walked)
+	}
+
+	// The parser and the reference reject the same inputs.
+	data, err := ReadBytes(file)
+	if err != nil || refErr != nil {
+		if err != ErrNoExifData || refErr == nil {
+			t.Errorf("%s: parser returned %v, reference returned %v", name, err, refErr)
+		}
+		return
+	}
+	assert.Equal(t, block, data.rawExif, "%s: exif block", name)
+
+	assert.Equal(t, ref.Order, data.Order, name)
+
+	// Entries point to their value in file, and decode without panicking.
+	for _, e := range data.Entries {
+		assert.LessOrEqual(t, e.Offset+e.Length, len(file), "%s: %s", name, e.String())
+		_, _ = e.Decode()
+	}
+	_, err = data.Layout()
+	assert.NoError(t, err, name)
+
+	var unexplained []string
+	for _, diff := range diffRaw(ref, data.Raw) {
+		if diff.Explained == "" {
+			unexplained = append(unexplained, diff.String())
+		} else {
+			t.Logf("%s: %s", name, diff)
+		}
+	}
+	if len(unexplained) != 0 {
+		t.Errorf("%s: Raw does not match the file (%s):\n\t%s",
+			name, strings.Join(ref.Problems, ", "), strings.Join(unexplained, "\n\t"))
+	}
+}
+
+func TestDifferential(t *testing.T) {
+	for _, file := range []string{
+		"_examples/resources/test.jpg",
+		"_examples/resources/testlocation.jpg",
+	} {
+		src, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		data, err := ReadBytes(src)
+		require.NoError(t, err)
+		block := refExifBlock(src)
+		assert.Equal(t, block, data.rawExif, file)
+		assert.Equal(t, bytes.Index(src, block), data.exifOffset, file)
+
+		checkDifferential(t, file, src)
+	}
+
+	for name, build := range malformedFiles {
+		checkDifferential(t, name, build())
+	}
+}
+
+func FuzzRead(f *testing.F) {
+	for _, file := range []string{
+		"_examples/resources/test.jpg",
+		"_examples/resources/testlocation.jpg",
+	} {
+		src, err := os.ReadFile(file)
+		require.NoError(f, err)
+		f.Add(refExifBlock(src))
+	}
+	for _, build := range malformedFiles {
+		src := build()
+		f.Add(src)
+		f.Add(refExifBlock(src))
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		checkDifferential(t, "input", b)
+	})
+}
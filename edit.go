@@ -0,0 +1,133 @@
+package exif
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error messages.
+var (
+	ErrTagNotAllowed = errors.New(`tag not allowed in ifd`)
+)
+
+// ifdPointers maps each sub-IFD to the parent IFD and tag that point to it.
+var ifdPointers = map[Ifd]struct {
+	parent Ifd
+	tag    Tag
+}{
+	IfdExif:             {Ifd0, EXIF_TAG_EXIF_IFD_POINTER},
+	IfdGps:              {Ifd0, EXIF_TAG_GPS_INFO_IFD_POINTER},
+	IfdInterOperability: {IfdExif, EXIF_TAG_INTEROPERABILITY_IFD_POINTER},
+}
+
+// Set adds or replaces tag in ifd. See Entry.SetValue for the accepted value
+// types. Pointers to the IFD are added when missing. The entries of the IFDs
+// numbered from IfdMaxCount, such as SubIFDs, are read-only, see TagAllowed.
+func (d *Data) Set(ifd Ifd, tag Tag, value interface{}) error {
+	e, err := d.newValueEntry(ifd, tag, value)
+	if err != nil {
+		return err
+	}
+
+	d.SetEntry(e)
+	return nil
+}
+
+// newValueEntry returns the entry Set would store, without storing it.
+func (d *Data) newValueEntry(ifd Ifd, tag Tag, value interface{}) (*Entry, error) {
+	if !TagAllowed(ifd, tag) {
+		return nil, fmt.Errorf("%w: tag 0x%04x in ifd %d", ErrTagNotAllowed, uint16(tag), ifd)
+	}
+
+	e := d.NewEntry(ifd, tag)
+	if err := e.SetValue(value); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// SetEntry stores e as is, adding pointers to its IFD when missing. e
+// replaces the first occurrence of its tag in Entries, or is added after the
+// other entries of its IFD.
+func (d *Data) SetEntry(e *Entry) {
+	d.putEntry(*e)
+	d.addPointers(e.Ifd)
+}
+
+// Delete removes every occurrence of tag from ifd. Pointers to the IFD are
+// removed along with its last entry.
+func (d *Data) Delete(ifd Ifd, tag Tag) {
+	d.removeEntry(ifd, tag)
+	d.removePointers(ifd)
+}
+
+func (d *Data) putEntry(e Entry) {
+	if d.Raw == nil {
+		d.Raw = make(map[IfdTag]Entry)
+	}
+	d.Raw[NewIfdTag(uint16(e.Ifd), uint16(e.Tag))] = e
+
+	pos := len(d.Entries)
+	for i, cur := range d.Entries {
+		if cur.Ifd == e.Ifd && cur.Tag == e.Tag {
+			d.Entries[i] = e
+			return
+		}
+		if cur.Ifd > e.Ifd && pos == len(d.Entries) {
+			pos = i
+		}
+	}
+
+	d.Entries = append(d.Entries, Entry{})
+	copy(d.Entries[pos+1:], d.Entries[pos:])
+	d.Entries[pos] = e
+}
+
+func (d *Data) removeEntry(ifd Ifd, tag Tag) {
+	delete(d.Raw, NewIfdTag(uint16(ifd), uint16(tag)))
+
+	entries := d.Entries[:0]
+	for _, e := range d.Entries {
+		if e.Ifd != ifd || e.Tag != tag {
+			entries = append(entries, e)
+		}
+	}
+	d.Entries = entries
+}
+
+func (d *Data) addPointers(ifd Ifd) {
+	for {
+		ptr, ok := ifdPointers[ifd]
+		if !ok {
+			return
+		}
+		key := NewIfdTag(uint16(ptr.parent), uint16(ptr.tag))
+		if _, ok := d.Raw[key]; !ok {
+			// The actual offset is computed when saving.
+			e := d.NewEntry(ptr.parent, ptr.tag)
+			e.SetUint32s([]uint32{0})
+			d.putEntry(*e)
+		}
+		ifd = ptr.parent
+	}
+}
+
+func (d *Data) removePointers(ifd Ifd) {
+	for {
+		ptr, ok := ifdPointers[ifd]
+		if !ok || !d.ifdEmpty(ifd) {
+			return
+		}
+		d.removeEntry(ptr.parent, ptr.tag)
+		ifd = ptr.parent
+	}
+}
+
+func (d *Data) ifdEmpty(ifd Ifd) bool {
+	for _, e := range d.Raw {
+		if e.Ifd == ifd {
+			return false
+		}
+	}
+	return true
+}
@@ -5,13 +5,58 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
+	"unicode/utf16"
 )
 
 var (
-	ErrLengthNotMatch = errors.New("rational length not match")
+	ErrLengthNotMatch = errors.New("value length not match")
 	ErrFormatNotMatch = errors.New("cannot match request format")
 )
 
+// EntryError reports why the value of an entry cannot be read or stored. It
+// wraps one of the sentinel errors, such as ErrFormatNotMatch, which
+// errors.Is matches.
+type EntryError struct {
+	Ifd Ifd
+	Tag Tag
+	// Want is the format expected, Got the format of the entry.
+	Want EntryFormat
+	Got  EntryFormat
+	// Components and RawLen describe the value of the entry.
+	Components int
+	RawLen     int
+	Err        error
+}
+
+func (e *EntryError) Error() string {
+	msg := fmt.Sprintf("%s %s: %s", e.Ifd, tagLabel(e.Ifd, e.Tag), e.Err)
+	switch {
+	case e.Want != e.Got:
+		return fmt.Sprintf("%s: want %s, got %s", msg, e.Want, e.Got)
+	case e.Components != 0 || e.RawLen != 0:
+		return fmt.Sprintf("%s: %d components of %s in %d bytes", msg, e.Components, e.Got, e.RawLen)
+	}
+	return msg
+}
+
+func (e *EntryError) Unwrap() error {
+	return e.Err
+}
+
+// newError returns an EntryError about reading e as want.
+func (e *Entry) newError(want EntryFormat, err error) error {
+	return &EntryError{
+		Ifd:        e.Ifd,
+		Tag:        e.Tag,
+		Want:       want,
+		Got:        e.Format,
+		Components: e.Components,
+		RawLen:     len(e.Raw),
+		Err:        err,
+	}
+}
+
 type Entry struct {
 	Ifd        Ifd
 	Tag        Tag
@@ -19,20 +64,34 @@ type Entry struct {
 	Components int
 	Raw        []byte
 	order      binary.ByteOrder
+
+	// Offset is the position of the value in the data the entry was read
+	// from, and Length its size there. Both are 0 for entries that were not
+	// read, such as the ones added by Set.
+	Offset int
+	Length int
 }
 
 func (e *Entry) String() string {
-	return fmt.Sprintf("<ifd:%d, tag:%d, fmt:%d, len:%d, total:%d>",
-		e.Ifd, e.Tag, e.Format, e.Components, len(e.Raw))
+	return fmt.Sprintf("<ifd:%s, tag:%s, fmt:%s, len:%d, total:%d>",
+		e.Ifd, tagLabel(e.Ifd, e.Tag), e.Format, e.Components, len(e.Raw))
+}
+
+// tagLabel returns the name of tag, or its number when it is not known.
+func tagLabel(ifd Ifd, tag Tag) string {
+	if name := tag.Name(ifd); name != "" {
+		return name
+	}
+	return fmt.Sprintf("0x%04x", uint16(tag))
 }
 
 func (e *Entry) ReadAsUnsignedRational() ([]UnsignedRational, error) {
 	if e.Format != FormatUnsignedRational {
-		return nil, ErrFormatNotMatch
+		return nil, e.newError(FormatUnsignedRational, ErrFormatNotMatch)
 	}
 	var out = make([]UnsignedRational, e.Components)
 	if len(e.Raw) != e.Components*8 {
-		return nil, ErrLengthNotMatch
+		return nil, e.newError(e.Format, ErrLengthNotMatch)
 	}
 
 	for i := 0; i != e.Components; i++ {
@@ -47,12 +106,12 @@ func (e *Entry) ReadAsUnsignedRational() ([]UnsignedRational, error) {
 
 func (e *Entry) ReadAsSignedRational() ([]SignedRational, error) {
 	if e.Format != FormatSignedRational {
-		return nil, ErrFormatNotMatch
+		return nil, e.newError(FormatSignedRational, ErrFormatNotMatch)
 	}
 
 	var out = make([]SignedRational, e.Components)
 	if len(e.Raw) != e.Components*8 {
-		return nil, ErrLengthNotMatch
+		return nil, e.newError(e.Format, ErrLengthNotMatch)
 	}
 
 	for i := 0; i != e.Components; i++ {
@@ -69,19 +128,47 @@ func (e *Entry) ReadAsSignedRational() ([]SignedRational, error) {
 
 func (e *Entry) ReadAsString() (string, error) {
 	if e.Format != FormatAscii {
-		return "", ErrFormatNotMatch
+		return "", e.newError(FormatAscii, ErrFormatNotMatch)
 	}
 
 	return string(e.Raw), nil
 }
 
+// ReadAsEncodedString decodes a string preceded by the 8 byte character code
+// used by tags such as UserComment and GPSProcessingMethod. Values without a
+// known character code are returned as is.
+func (e *Entry) ReadAsEncodedString() (string, error) {
+	switch e.Format {
+	case FormatUndefined, FormatUnsignedByte, FormatAscii:
+	default:
+		return "", e.newError(FormatUndefined, ErrFormatNotMatch)
+	}
+
+	raw := e.Raw
+	if len(raw) >= 8 {
+		switch string(raw[:8]) {
+		case "UNICODE\x00":
+			raw = raw[8:]
+			u := make([]uint16, len(raw)/2)
+			for i := range u {
+				u[i] = e.byteOrder().Uint16(raw[i*2 : (i+1)*2])
+			}
+			return strings.TrimRight(string(utf16.Decode(u)), "\x00 "), nil
+		case "ASCII\x00\x00\x00", "JIS\x00\x00\x00\x00\x00", "\x00\x00\x00\x00\x00\x00\x00\x00":
+			raw = raw[8:]
+		}
+	}
+
+	return strings.TrimRight(string(raw), "\x00 "), nil
+}
+
 func (e *Entry) ReadAsUnsignedShort() ([]uint16, error) {
 	if e.Format != FormatUnsignedShort {
-		return nil, ErrFormatNotMatch
+		return nil, e.newError(FormatUnsignedShort, ErrFormatNotMatch)
 	}
 
 	if len(e.Raw) != 2*e.Components {
-		return nil, ErrLengthNotMatch
+		return nil, e.newError(e.Format, ErrLengthNotMatch)
 	}
 
 	var out = make([]uint16, e.Components)
@@ -94,11 +181,11 @@ func (e *Entry) ReadAsUnsignedShort() ([]uint16, error) {
 
 func (e *Entry) ReadAsSignedShort() ([]int16, error) {
 	if e.Format != FormatSignedShort {
-		return nil, ErrFormatNotMatch
+		return nil, e.newError(FormatSignedShort, ErrFormatNotMatch)
 	}
 
 	if len(e.Raw) != 2*e.Components {
-		return nil, ErrLengthNotMatch
+		return nil, e.newError(e.Format, ErrLengthNotMatch)
 	}
 
 	var out = make([]int16, e.Components)
@@ -116,7 +203,7 @@ func (e *Entry) GetRaw() ([]byte, error) {
 
 func (e *Entry) GetInt8() ([]int8, error) {
 	if e.Format != FormatSignedByte {
-		return nil, ErrFormatNotMatch
+		return nil, e.newError(FormatSignedByte, ErrFormatNotMatch)
 	}
 
 	var out = make([]int8, len(e.Raw))
@@ -128,11 +215,11 @@ func (e *Entry) GetInt8() ([]int8, error) {
 
 func (e *Entry) GetUint32() ([]uint32, error) {
 	if e.Format != FormatUnsignedLong {
-		return nil, ErrFormatNotMatch
+		return nil, e.newError(FormatUnsignedLong, ErrFormatNotMatch)
 	}
 
 	if len(e.Raw) != e.Components*4 {
-		return nil, ErrLengthNotMatch
+		return nil, e.newError(e.Format, ErrLengthNotMatch)
 	}
 
 	var out = make([]uint32, e.Components)
@@ -145,11 +232,11 @@ func (e *Entry) GetUint32() ([]uint32, error) {
 
 func (e *Entry) GetInt32() ([]int32, error) {
 	if e.Format != FormatSignedLong {
-		return nil, ErrFormatNotMatch
+		return nil, e.newError(FormatSignedLong, ErrFormatNotMatch)
 	}
 
 	if len(e.Raw) != e.Components*4 {
-		return nil, ErrLengthNotMatch
+		return nil, e.newError(e.Format, ErrLengthNotMatch)
 	}
 
 	var out = make([]int32, e.Components)
@@ -162,11 +249,11 @@ func (e *Entry) GetInt32() ([]int32, error) {
 
 func (e *Entry) GetFloat32() ([]float32, error) {
 	if e.Format != FormatFloat {
-		return nil, ErrFormatNotMatch
+		return nil, e.newError(FormatFloat, ErrFormatNotMatch)
 	}
 
 	if len(e.Raw) != e.Components*4 {
-		return nil, ErrLengthNotMatch
+		return nil, e.newError(e.Format, ErrLengthNotMatch)
 	}
 
 	var out = make([]float32, e.Components)
@@ -180,16 +267,16 @@ func (e *Entry) GetFloat32() ([]float32, error) {
 
 func (e *Entry) GetDouble64() ([]float64, error) {
 	if e.Format != FormatDouble {
-		return nil, ErrFormatNotMatch
+		return nil, e.newError(FormatDouble, ErrFormatNotMatch)
 	}
 
 	if len(e.Raw) != e.Components*8 {
-		return nil, ErrLengthNotMatch
+		return nil, e.newError(e.Format, ErrLengthNotMatch)
 	}
 
 	var out = make([]float64, e.Components)
 	for i := 0; i != e.Components; i++ {
-		v := e.order.Uint64(e.Raw[i*4 : (i+1)*4])
+		v := e.order.Uint64(e.Raw[i*8 : (i+1)*8])
 		cur := math.Float64frombits(v)
 		out[i] = cur
 	}
@@ -238,14 +325,210 @@ func (e *Entry) GetValue() (interface{}, error) {
 		return e.GetDouble64()
 	}
 
-	return nil, ErrUnknownFormat
+	return nil, e.newError(e.Format, ErrUnknownFormat)
+}
+
+// NewEntry returns an empty entry for tag in ifd, using the byte order of d.
+func (d *Data) NewEntry(ifd Ifd, tag Tag) *Entry {
+	return &Entry{
+		Ifd:   ifd,
+		Tag:   tag,
+		order: d.Order,
+	}
+}
+
+// NewAsciiEntry returns an entry for tag in ifd holding value.
+func (d *Data) NewAsciiEntry(ifd Ifd, tag Tag, value string) (*Entry, error) {
+	e := d.NewEntry(ifd, tag)
+	if err := e.SetAscii(value); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *Entry) byteOrder() binary.ByteOrder {
+	if e.order == nil {
+		return binary.BigEndian
+	}
+	return e.order
+}
+
+// set replaces the value of the entry, after making sure format is allowed
+// for its tag.
+func (e *Entry) set(format EntryFormat, components int, raw []byte) error {
+	if err := checkFormat(e.Ifd, e.Tag, format); err != nil {
+		return err
+	}
+
+	e.Format = format
+	e.Components = components
+	e.Raw = raw
+	e.order = e.byteOrder()
+	return nil
+}
+
+// SetAscii stores value as a NUL terminated string.
+func (e *Entry) SetAscii(value string) error {
+	raw := make([]byte, len(value)+1)
+	copy(raw, value)
+	return e.set(FormatAscii, len(raw), raw)
+}
+
+func (e *Entry) SetBytes(value []byte) error {
+	raw := append([]byte(nil), value...)
+	return e.set(FormatUnsignedByte, len(raw), raw)
+}
+
+func (e *Entry) SetUndefined(value []byte) error {
+	raw := append([]byte(nil), value...)
+	return e.set(FormatUndefined, len(raw), raw)
+}
+
+func (e *Entry) SetInt8s(value []int8) error {
+	raw := make([]byte, len(value))
+	for i, v := range value {
+		raw[i] = byte(v)
+	}
+	return e.set(FormatSignedByte, len(raw), raw)
+}
+
+func (e *Entry) SetUint16s(value []uint16) error {
+	order := e.byteOrder()
+	raw := make([]byte, len(value)*2)
+	for i, v := range value {
+		order.PutUint16(raw[i*2:(i+1)*2], v)
+	}
+	return e.set(FormatUnsignedShort, len(value), raw)
+}
+
+func (e *Entry) SetInt16s(value []int16) error {
+	order := e.byteOrder()
+	raw := make([]byte, len(value)*2)
+	for i, v := range value {
+		order.PutUint16(raw[i*2:(i+1)*2], uint16(v))
+	}
+	return e.set(FormatSignedShort, len(value), raw)
+}
+
+func (e *Entry) SetUint32s(value []uint32) error {
+	order := e.byteOrder()
+	raw := make([]byte, len(value)*4)
+	for i, v := range value {
+		order.PutUint32(raw[i*4:(i+1)*4], v)
+	}
+	return e.set(FormatUnsignedLong, len(value), raw)
+}
+
+func (e *Entry) SetInt32s(value []int32) error {
+	order := e.byteOrder()
+	raw := make([]byte, len(value)*4)
+	for i, v := range value {
+		order.PutUint32(raw[i*4:(i+1)*4], uint32(v))
+	}
+	return e.set(FormatSignedLong, len(value), raw)
+}
+
+func (e *Entry) SetUnsignedRationals(value []UnsignedRational) error {
+	order := e.byteOrder()
+	raw := make([]byte, len(value)*8)
+	for i, v := range value {
+		order.PutUint32(raw[i*8:i*8+4], v.Numerator)
+		order.PutUint32(raw[i*8+4:(i+1)*8], v.Denominator)
+	}
+	return e.set(FormatUnsignedRational, len(value), raw)
+}
+
+func (e *Entry) SetSignedRationals(value []SignedRational) error {
+	order := e.byteOrder()
+	raw := make([]byte, len(value)*8)
+	for i, v := range value {
+		order.PutUint32(raw[i*8:i*8+4], uint32(v.Numerator))
+		order.PutUint32(raw[i*8+4:(i+1)*8], uint32(v.Denominator))
+	}
+	return e.set(FormatSignedRational, len(value), raw)
+}
+
+func (e *Entry) SetFloat32s(value []float32) error {
+	order := e.byteOrder()
+	raw := make([]byte, len(value)*4)
+	for i, v := range value {
+		order.PutUint32(raw[i*4:(i+1)*4], math.Float32bits(v))
+	}
+	return e.set(FormatFloat, len(value), raw)
+}
+
+func (e *Entry) SetFloat64s(value []float64) error {
+	order := e.byteOrder()
+	raw := make([]byte, len(value)*8)
+	for i, v := range value {
+		order.PutUint64(raw[i*8:(i+1)*8], math.Float64bits(v))
+	}
+	return e.set(FormatDouble, len(value), raw)
+}
+
+/*
+SetValue accepts the types returned by GetValue, or a single element of them.
+A []byte is stored as FormatUndefined when the tag requires it, and as
+FormatUnsignedByte otherwise.
+*/
+func (e *Entry) SetValue(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return e.SetAscii(v)
+	case []byte:
+		if checkFormat(e.Ifd, e.Tag, FormatUnsignedByte) != nil {
+			return e.SetUndefined(v)
+		}
+		return e.SetBytes(v)
+	case byte:
+		return e.SetValue([]byte{v})
+	case []uint16:
+		return e.SetUint16s(v)
+	case uint16:
+		return e.SetUint16s([]uint16{v})
+	case []uint32:
+		return e.SetUint32s(v)
+	case uint32:
+		return e.SetUint32s([]uint32{v})
+	case []UnsignedRational:
+		return e.SetUnsignedRationals(v)
+	case UnsignedRational:
+		return e.SetUnsignedRationals([]UnsignedRational{v})
+	case []int8:
+		return e.SetInt8s(v)
+	case int8:
+		return e.SetInt8s([]int8{v})
+	case []int16:
+		return e.SetInt16s(v)
+	case int16:
+		return e.SetInt16s([]int16{v})
+	case []int32:
+		return e.SetInt32s(v)
+	case int32:
+		return e.SetInt32s([]int32{v})
+	case []SignedRational:
+		return e.SetSignedRationals(v)
+	case SignedRational:
+		return e.SetSignedRationals([]SignedRational{v})
+	case []float32:
+		return e.SetFloat32s(v)
+	case float32:
+		return e.SetFloat32s([]float32{v})
+	case []float64:
+		return e.SetFloat64s(v)
+	case float64:
+		return e.SetFloat64s([]float64{v})
+	}
+
+	return ErrUnknownFormat
 }
 
 // ifd: [0: 2], tag: [2: 4], littleEndian
 type IfdTag [4]byte
 
 func (m *IfdTag) String() string {
-	return fmt.Sprintf("<ifd: %d, tag: %d>", m.Ifd(), m.Tag())
+	ifd, tag := Ifd(m.Ifd()), Tag(m.Tag())
+	return fmt.Sprintf("<ifd: %s, tag: %s>", ifd, tagLabel(ifd, tag))
 }
 
 func NewIfdTag(ifd, tag uint16) IfdTag {
@@ -277,3 +560,23 @@ type UnsignedRational struct {
 func (u *UnsignedRational) String() string {
 	return fmt.Sprintf("%d/%d", u.Numerator, u.Denominator)
 }
+
+// Float64 returns the value of the rational, NaN when the denominator is 0.
+func (u *UnsignedRational) Float64() float64 {
+	if u.Denominator == 0 {
+		return math.NaN()
+	}
+	return float64(u.Numerator) / float64(u.Denominator)
+}
+
+func (s *SignedRational) String() string {
+	return fmt.Sprintf("%d/%d", s.Numerator, s.Denominator)
+}
+
+// Float64 returns the value of the rational, NaN when the denominator is 0.
+func (s *SignedRational) Float64() float64 {
+	if s.Denominator == 0 {
+		return math.NaN()
+	}
+	return float64(s.Numerator) / float64(s.Denominator)
+}
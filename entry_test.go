@@ -0,0 +1,131 @@
+package exif
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntrySetters(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		data := New()
+		data.Order = order
+
+		e, err := data.NewAsciiEntry(Ifd0, EXIF_TAG_MAKE, "ACME")
+		require.NoError(t, err)
+		assert.Equal(t, 5, e.Components)
+		assert.Equal(t, []byte("ACME\x00"), e.Raw)
+
+		e = data.NewEntry(IfdGps, EXIF_TAG_GPS_LATITUDE)
+		dms := []UnsignedRational{{25, 1}, {21, 1}, {3210, 100}}
+		require.NoError(t, e.SetUnsignedRationals(dms))
+		assert.Equal(t, 3, e.Components)
+		assert.Len(t, e.Raw, 24)
+		rs, err := e.ReadAsUnsignedRational()
+		require.NoError(t, err)
+		assert.Equal(t, dms, rs)
+
+		e = data.NewEntry(Ifd0, EXIF_TAG_ORIENTATION)
+		require.NoError(t, e.SetUint16s([]uint16{6}))
+		us, err := e.ReadAsUnsignedShort()
+		require.NoError(t, err)
+		assert.Equal(t, []uint16{6}, us)
+
+		e = data.NewEntry(IfdExif, EXIF_TAG_EXPOSURE_BIAS_VALUE)
+		bias := []SignedRational{{-2, 3}}
+		require.NoError(t, e.SetSignedRationals(bias))
+		ss, err := e.ReadAsSignedRational()
+		require.NoError(t, err)
+		assert.Equal(t, bias, ss)
+
+		// Private tags are not checked.
+		e = data.NewEntry(IfdExif, Tag(0xfeed))
+		fs := []float64{1.5, -2.25}
+		require.NoError(t, e.SetFloat64s(fs))
+		assert.Equal(t, 2, e.Components)
+		ds, err := e.GetDouble64()
+		require.NoError(t, err)
+		assert.Equal(t, fs, ds)
+	}
+}
+
+func TestEntrySetterFormatCheck(t *testing.T) {
+	data := New()
+
+	e := data.NewEntry(Ifd0, EXIF_TAG_ORIENTATION)
+	require.NoError(t, e.SetUint16s([]uint16{1}))
+	assert.True(t, errors.Is(e.SetUint32s([]uint32{1}), ErrFormatNotMatch))
+	assert.Equal(t, FormatUnsignedShort, e.Format)
+
+	_, err := data.NewAsciiEntry(IfdGps, EXIF_TAG_GPS_LATITUDE, "25")
+	assert.True(t, errors.Is(err, ErrFormatNotMatch))
+
+	e = data.NewEntry(IfdExif, EXIF_TAG_PIXEL_X_DIMENSION)
+	assert.NoError(t, e.SetUint16s([]uint16{640}))
+	assert.NoError(t, e.SetUint32s([]uint32{640}))
+}
+
+func TestEntryError(t *testing.T) {
+	data := New()
+	e := data.NewEntry(Ifd0, EXIF_TAG_ORIENTATION)
+	require.NoError(t, e.SetUint16s([]uint16{1}))
+
+	_, err := e.GetUint32()
+	var entryErr *EntryError
+	require.True(t, errors.As(err, &entryErr))
+	assert.True(t, errors.Is(err, ErrFormatNotMatch))
+	assert.Equal(t, &EntryError{
+		Ifd:        Ifd0,
+		Tag:        EXIF_TAG_ORIENTATION,
+		Want:       FormatUnsignedLong,
+		Got:        FormatUnsignedShort,
+		Components: 1,
+		RawLen:     2,
+		Err:        ErrFormatNotMatch,
+	}, entryErr)
+	assert.Contains(t, err.Error(), "Orientation")
+
+	e.Raw = e.Raw[:1]
+	_, err = e.ReadAsUnsignedShort()
+	assert.True(t, errors.Is(err, ErrLengthNotMatch))
+	assert.Contains(t, err.Error(), "1 components of Short in 1 bytes")
+
+	// Helper errors name the missing tag.
+	_, err = NewHelper(data).GetValue(IfdExif, EXIF_TAG_EXPOSURE_TIME)
+	require.True(t, errors.As(err, &entryErr))
+	assert.True(t, errors.Is(err, ErrNotFoundEntry))
+	assert.Equal(t, IfdExif, entryErr.Ifd)
+	assert.Equal(t, EXIF_TAG_EXPOSURE_TIME, entryErr.Tag)
+	assert.Contains(t, err.Error(), "ExposureTime")
+}
+
+func TestDataSetDelete(t *testing.T) {
+	data := New()
+
+	lat := []UnsignedRational{{25, 1}, {21, 1}, {3210, 100}}
+	require.NoError(t, data.Set(IfdGps, EXIF_TAG_GPS_LATITUDE, lat))
+	require.NoError(t, data.Set(IfdInterOperability, EXIF_TAG_INTEROPERABILITY_INDEX, "R98"))
+
+	helper := NewHelper(data)
+	v, err := helper.GetValue(IfdGps, EXIF_TAG_GPS_LATITUDE)
+	require.NoError(t, err)
+	assert.Equal(t, lat, v)
+	assert.NotNil(t, helper.GetEntry(uint16(Ifd0), uint16(EXIF_TAG_GPS_INFO_IFD_POINTER)))
+	assert.NotNil(t, helper.GetEntry(uint16(Ifd0), uint16(EXIF_TAG_EXIF_IFD_POINTER)))
+	assert.NotNil(t, helper.GetEntry(uint16(IfdExif), uint16(EXIF_TAG_INTEROPERABILITY_IFD_POINTER)))
+
+	err = data.Set(Ifd0, EXIF_TAG_FNUMBER, UnsignedRational{28, 10})
+	assert.True(t, errors.Is(err, ErrTagNotAllowed))
+	assert.True(t, errors.Is(data.Set(IfdExif, EXIF_TAG_FNUMBER, "f/2.8"), ErrFormatNotMatch))
+
+	data.Delete(IfdGps, EXIF_TAG_GPS_LATITUDE)
+	assert.Nil(t, helper.GetEntry(uint16(IfdGps), uint16(EXIF_TAG_GPS_LATITUDE)))
+	assert.Nil(t, helper.GetEntry(uint16(Ifd0), uint16(EXIF_TAG_GPS_INFO_IFD_POINTER)))
+	assert.NotNil(t, helper.GetEntry(uint16(Ifd0), uint16(EXIF_TAG_EXIF_IFD_POINTER)))
+
+	data.Delete(IfdInterOperability, EXIF_TAG_INTEROPERABILITY_INDEX)
+	assert.Empty(t, data.Raw)
+}
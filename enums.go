@@ -0,0 +1,539 @@
+package exif
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExposureProgram is the value of the ExposureProgram tag.
+type ExposureProgram uint16
+
+// Exposure programs.
+const (
+	ExposureProgramNotDefined       ExposureProgram = 0
+	ExposureProgramManual           ExposureProgram = 1
+	ExposureProgramNormal           ExposureProgram = 2
+	ExposureProgramAperturePriority ExposureProgram = 3
+	ExposureProgramShutterPriority  ExposureProgram = 4
+	ExposureProgramCreative         ExposureProgram = 5
+	ExposureProgramAction           ExposureProgram = 6
+	ExposureProgramPortrait         ExposureProgram = 7
+	ExposureProgramLandscape        ExposureProgram = 8
+)
+
+var exposureProgramNames = map[uint16]string{
+	0: "Not defined",
+	1: "Manual",
+	2: "Normal program",
+	3: "Aperture priority",
+	4: "Shutter priority",
+	5: "Creative program (biased toward depth of field)",
+	6: "Creative program (biased toward fast shutter speed)",
+	7: "Portrait mode (for closeup photos with the background out of focus)",
+	8: "Landscape mode (for landscape photos with the background in focus)",
+}
+
+func (v ExposureProgram) String() string {
+	return enumName(exposureProgramNames, "ExposureProgram", uint16(v))
+}
+
+// MeteringMode is the value of the MeteringMode tag.
+type MeteringMode uint16
+
+// Metering modes.
+const (
+	MeteringModeUnknown               MeteringMode = 0
+	MeteringModeAverage               MeteringMode = 1
+	MeteringModeCenterWeightedAverage MeteringMode = 2
+	MeteringModeSpot                  MeteringMode = 3
+	MeteringModeMultiSpot             MeteringMode = 4
+	MeteringModePattern               MeteringMode = 5
+	MeteringModePartial               MeteringMode = 6
+	MeteringModeOther                 MeteringMode = 255
+)
+
+var meteringModeNames = map[uint16]string{
+	0:   "Unknown",
+	1:   "Average",
+	2:   "Center-weighted average",
+	3:   "Spot",
+	4:   "Multi spot",
+	5:   "Pattern",
+	6:   "Partial",
+	255: "Other",
+}
+
+func (v MeteringMode) String() string {
+	return enumName(meteringModeNames, "MeteringMode", uint16(v))
+}
+
+// LightSource is the value of the LightSource tag.
+type LightSource uint16
+
+// Light sources.
+const (
+	LightSourceUnknown              LightSource = 0
+	LightSourceDaylight             LightSource = 1
+	LightSourceFluorescent          LightSource = 2
+	LightSourceTungsten             LightSource = 3
+	LightSourceFlash                LightSource = 4
+	LightSourceFineWeather          LightSource = 9
+	LightSourceCloudyWeather        LightSource = 10
+	LightSourceShade                LightSource = 11
+	LightSourceDaylightFluorescent  LightSource = 12
+	LightSourceDayWhiteFluorescent  LightSource = 13
+	LightSourceCoolWhiteFluorescent LightSource = 14
+	LightSourceWhiteFluorescent     LightSource = 15
+	LightSourceWarmWhiteFluorescent LightSource = 16
+	LightSourceStandardLightA       LightSource = 17
+	LightSourceStandardLightB       LightSource = 18
+	LightSourceStandardLightC       LightSource = 19
+	LightSourceD55                  LightSource = 20
+	LightSourceD65                  LightSource = 21
+	LightSourceD75                  LightSource = 22
+	LightSourceD50                  LightSource = 23
+	LightSourceISOStudioTungsten    LightSource = 24
+	LightSourceOther                LightSource = 255
+)
+
+var lightSourceNames = map[uint16]string{
+	0:   "Unknown",
+	1:   "Daylight",
+	2:   "Fluorescent",
+	3:   "Tungsten incandescent light",
+	4:   "Flash",
+	9:   "Fine weather",
+	10:  "Cloudy weather",
+	11:  "Shade",
+	12:  "Daylight fluorescent",
+	13:  "Day white fluorescent",
+	14:  "Cool white fluorescent",
+	15:  "White fluorescent",
+	16:  "Warm white fluorescent",
+	17:  "Standard light A",
+	18:  "Standard light B",
+	19:  "Standard light C",
+	20:  "D55",
+	21:  "D65",
+	22:  "D75",
+	23:  "D50",
+	24:  "ISO studio tungsten",
+	255: "Other",
+}
+
+func (v LightSource) String() string {
+	return enumName(lightSourceNames, "LightSource", uint16(v))
+}
+
+// WhiteBalance is the value of the WhiteBalance tag.
+type WhiteBalance uint16
+
+// White balance modes.
+const (
+	WhiteBalanceAuto   WhiteBalance = 0
+	WhiteBalanceManual WhiteBalance = 1
+)
+
+var whiteBalanceNames = map[uint16]string{
+	0: "Auto white balance",
+	1: "Manual white balance",
+}
+
+func (v WhiteBalance) String() string {
+	return enumName(whiteBalanceNames, "WhiteBalance", uint16(v))
+}
+
+// SceneCaptureType is the value of the SceneCaptureType tag.
+type SceneCaptureType uint16
+
+// Scene capture types.
+const (
+	SceneCaptureTypeStandard   SceneCaptureType = 0
+	SceneCaptureTypeLandscape  SceneCaptureType = 1
+	SceneCaptureTypePortrait   SceneCaptureType = 2
+	SceneCaptureTypeNightScene SceneCaptureType = 3
+)
+
+var sceneCaptureTypeNames = map[uint16]string{
+	0: "Standard",
+	1: "Landscape",
+	2: "Portrait",
+	3: "Night scene",
+}
+
+func (v SceneCaptureType) String() string {
+	return enumName(sceneCaptureTypeNames, "SceneCaptureType", uint16(v))
+}
+
+// SensingMethod is the value of the SensingMethod tag.
+type SensingMethod uint16
+
+// Sensing methods.
+const (
+	SensingMethodNotDefined            SensingMethod = 1
+	SensingMethodOneChipColorArea      SensingMethod = 2
+	SensingMethodTwoChipColorArea      SensingMethod = 3
+	SensingMethodThreeChipColorArea    SensingMethod = 4
+	SensingMethodColorSequentialArea   SensingMethod = 5
+	SensingMethodTrilinear             SensingMethod = 7
+	SensingMethodColorSequentialLinear SensingMethod = 8
+)
+
+var sensingMethodNames = map[uint16]string{
+	1: "Not defined",
+	2: "One-chip color area sensor",
+	3: "Two-chip color area sensor",
+	4: "Three-chip color area sensor",
+	5: "Color sequential area sensor",
+	7: "Trilinear sensor",
+	8: "Color sequential linear sensor",
+}
+
+func (v SensingMethod) String() string {
+	return enumName(sensingMethodNames, "SensingMethod", uint16(v))
+}
+
+// CustomRendered is the value of the CustomRendered tag.
+type CustomRendered uint16
+
+// Rendering processes.
+const (
+	CustomRenderedNormal CustomRendered = 0
+	CustomRenderedCustom CustomRendered = 1
+)
+
+var customRenderedNames = map[uint16]string{
+	0: "Normal process",
+	1: "Custom process",
+}
+
+func (v CustomRendered) String() string {
+	return enumName(customRenderedNames, "CustomRendered", uint16(v))
+}
+
+// GainControl is the value of the GainControl tag.
+type GainControl uint16
+
+// Gain controls.
+const (
+	GainControlNone         GainControl = 0
+	GainControlLowGainUp    GainControl = 1
+	GainControlHighGainUp   GainControl = 2
+	GainControlLowGainDown  GainControl = 3
+	GainControlHighGainDown GainControl = 4
+)
+
+var gainControlNames = map[uint16]string{
+	0: "Normal",
+	1: "Low gain up",
+	2: "High gain up",
+	3: "Low gain down",
+	4: "High gain down",
+}
+
+func (v GainControl) String() string {
+	return enumName(gainControlNames, "GainControl", uint16(v))
+}
+
+// Contrast is the value of the Contrast tag.
+type Contrast uint16
+
+// Contrast processings.
+const (
+	ContrastNormal Contrast = 0
+	ContrastSoft   Contrast = 1
+	ContrastHard   Contrast = 2
+)
+
+var contrastNames = map[uint16]string{
+	0: "Normal",
+	1: "Soft",
+	2: "Hard",
+}
+
+func (v Contrast) String() string {
+	return enumName(contrastNames, "Contrast", uint16(v))
+}
+
+// Saturation is the value of the Saturation tag.
+type Saturation uint16
+
+// Saturation processings.
+const (
+	SaturationNormal Saturation = 0
+	SaturationLow    Saturation = 1
+	SaturationHigh   Saturation = 2
+)
+
+var saturationNames = map[uint16]string{
+	0: "Normal",
+	1: "Low saturation",
+	2: "High saturation",
+}
+
+func (v Saturation) String() string {
+	return enumName(saturationNames, "Saturation", uint16(v))
+}
+
+// Sharpness is the value of the Sharpness tag.
+type Sharpness uint16
+
+// Sharpness processings.
+const (
+	SharpnessNormal Sharpness = 0
+	SharpnessSoft   Sharpness = 1
+	SharpnessHard   Sharpness = 2
+)
+
+var sharpnessNames = map[uint16]string{
+	0: "Normal",
+	1: "Soft",
+	2: "Hard",
+}
+
+func (v Sharpness) String() string {
+	return enumName(sharpnessNames, "Sharpness", uint16(v))
+}
+
+// SubjectDistanceRange is the value of the SubjectDistanceRange tag.
+type SubjectDistanceRange uint16
+
+// Subject distance ranges.
+const (
+	SubjectDistanceRangeUnknown SubjectDistanceRange = 0
+	SubjectDistanceRangeMacro   SubjectDistanceRange = 1
+	SubjectDistanceRangeClose   SubjectDistanceRange = 2
+	SubjectDistanceRangeDistant SubjectDistanceRange = 3
+)
+
+var subjectDistanceRangeNames = map[uint16]string{
+	0: "Unknown",
+	1: "Macro",
+	2: "Close view",
+	3: "Distant view",
+}
+
+func (v SubjectDistanceRange) String() string {
+	return enumName(subjectDistanceRangeNames, "SubjectDistanceRange", uint16(v))
+}
+
+// ColorSpace is the value of the ColorSpace tag.
+type ColorSpace uint16
+
+// Color spaces. Adobe RGB is not in the specification, but cameras record
+// it.
+const (
+	ColorSpaceSRGB         ColorSpace = 1
+	ColorSpaceAdobeRGB     ColorSpace = 2
+	ColorSpaceUncalibrated ColorSpace = 0xffff
+)
+
+var colorSpaceNames = map[uint16]string{
+	1:      "sRGB",
+	2:      "Adobe RGB",
+	0xffff: "Uncalibrated",
+}
+
+func (v ColorSpace) String() string {
+	return enumName(colorSpaceNames, "ColorSpace", uint16(v))
+}
+
+// enumName returns the name of v, or the type and the number when it is not
+// known.
+func enumName(names map[uint16]string, typ string, v uint16) string {
+	if name, ok := names[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("%s(%d)", typ, v)
+}
+
+// FlashReturn tells whether the strobe return light was detected.
+type FlashReturn uint8
+
+// Strobe return light detection.
+const (
+	FlashReturnNoDetection FlashReturn = 0
+	FlashReturnNotDetected FlashReturn = 2
+	FlashReturnDetected    FlashReturn = 3
+)
+
+// FlashMode is the mode the flash was set to.
+type FlashMode uint8
+
+// Flash modes.
+const (
+	FlashModeUnknown    FlashMode = 0
+	FlashModeCompulsory FlashMode = 1
+	FlashModeSuppressed FlashMode = 2
+	FlashModeAuto       FlashMode = 3
+)
+
+// Flash is the value of the Flash tag, a bit field.
+type Flash struct {
+	Fired           bool
+	Return          FlashReturn
+	Mode            FlashMode
+	NoFunction      bool
+	RedEyeReduction bool
+}
+
+// NewFlash splits the value of the Flash tag.
+func NewFlash(v uint16) Flash {
+	return Flash{
+		Fired:           v&0x01 != 0,
+		Return:          FlashReturn(v >> 1 & 0x03),
+		Mode:            FlashMode(v >> 3 & 0x03),
+		NoFunction:      v&0x20 != 0,
+		RedEyeReduction: v&0x40 != 0,
+	}
+}
+
+// Value returns the value of the Flash tag.
+func (f Flash) Value() uint16 {
+	v := uint16(f.Return&0x03)<<1 | uint16(f.Mode&0x03)<<3
+	if f.Fired {
+		v |= 0x01
+	}
+	if f.NoFunction {
+		v |= 0x20
+	}
+	if f.RedEyeReduction {
+		v |= 0x40
+	}
+	return v
+}
+
+// String describes the flash, such as "Flash fired, auto mode".
+func (f Flash) String() string {
+	if f.NoFunction && !f.Fired {
+		return "No flash function"
+	}
+
+	parts := []string{"Flash did not fire"}
+	if f.Fired {
+		parts[0] = "Flash fired"
+	}
+	switch f.Mode {
+	case FlashModeCompulsory:
+		parts = append(parts, "compulsory flash mode")
+	case FlashModeSuppressed:
+		if f.Fired {
+			parts = append(parts, "compulsory flash suppression mode")
+		} else {
+			parts = append(parts, "compulsory flash mode")
+		}
+	case FlashModeAuto:
+		parts = append(parts, "auto mode")
+	}
+	if f.RedEyeReduction {
+		parts = append(parts, "red-eye reduction mode")
+	}
+	switch f.Return {
+	case FlashReturnNotDetected:
+		parts = append(parts, "return light not detected")
+	case FlashReturnDetected:
+		parts = append(parts, "return light detected")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// getShort returns the first value of a SHORT entry.
+func (h *Helper) getShort(ifd Ifd, tag Tag) (uint16, error) {
+	v, err := h.GetValue(ifd, tag)
+	if err != nil {
+		return 0, err
+	}
+
+	vs, ok := v.([]uint16)
+	if !ok {
+		return 0, h.entryError(ifd, tag, FormatUnsignedShort, ErrValueNotMatch)
+	}
+	if len(vs) == 0 {
+		return 0, h.entryError(ifd, tag, FormatUnsignedShort, ErrValueTooSmall)
+	}
+	return vs[0], nil
+}
+
+// GetFlash returns the flash status.
+func (h *Helper) GetFlash() (Flash, error) {
+	v, err := h.getShort(IfdExif, EXIF_TAG_FLASH)
+	return NewFlash(v), err
+}
+
+// GetExposureProgram returns the exposure program.
+func (h *Helper) GetExposureProgram() (ExposureProgram, error) {
+	v, err := h.getShort(IfdExif, EXIF_TAG_EXPOSURE_PROGRAM)
+	return ExposureProgram(v), err
+}
+
+// GetMeteringMode returns the metering mode.
+func (h *Helper) GetMeteringMode() (MeteringMode, error) {
+	v, err := h.getShort(IfdExif, EXIF_TAG_METERING_MODE)
+	return MeteringMode(v), err
+}
+
+// GetLightSource returns the light source.
+func (h *Helper) GetLightSource() (LightSource, error) {
+	v, err := h.getShort(IfdExif, EXIF_TAG_LIGHT_SOURCE)
+	return LightSource(v), err
+}
+
+// GetWhiteBalance returns the white balance mode.
+func (h *Helper) GetWhiteBalance() (WhiteBalance, error) {
+	v, err := h.getShort(IfdExif, EXIF_TAG_WHITE_BALANCE)
+	return WhiteBalance(v), err
+}
+
+// GetSceneCaptureType returns the scene capture type.
+func (h *Helper) GetSceneCaptureType() (SceneCaptureType, error) {
+	v, err := h.getShort(IfdExif, EXIF_TAG_SCENE_CAPTURE_TYPE)
+	return SceneCaptureType(v), err
+}
+
+// GetSensingMethod returns the sensor type.
+func (h *Helper) GetSensingMethod() (SensingMethod, error) {
+	v, err := h.getShort(IfdExif, EXIF_TAG_SENSING_METHOD)
+	return SensingMethod(v), err
+}
+
+// GetCustomRendered returns the rendering process.
+func (h *Helper) GetCustomRendered() (CustomRendered, error) {
+	v, err := h.getShort(IfdExif, EXIF_TAG_CUSTOM_RENDERED)
+	return CustomRendered(v), err
+}
+
+// GetGainControl returns the gain control.
+func (h *Helper) GetGainControl() (GainControl, error) {
+	v, err := h.getShort(IfdExif, EXIF_TAG_GAIN_CONTROL)
+	return GainControl(v), err
+}
+
+// GetContrast returns the contrast processing.
+func (h *Helper) GetContrast() (Contrast, error) {
+	v, err := h.getShort(IfdExif, EXIF_TAG_CONTRAST)
+	return Contrast(v), err
+}
+
+// GetSaturation returns the saturation processing.
+func (h *Helper) GetSaturation() (Saturation, error) {
+	v, err := h.getShort(IfdExif, EXIF_TAG_SATURATION)
+	return Saturation(v), err
+}
+
+// GetSharpness returns the sharpness processing.
+func (h *Helper) GetSharpness() (Sharpness, error) {
+	v, err := h.getShort(IfdExif, EXIF_TAG_SHARPNESS)
+	return Sharpness(v), err
+}
+
+// GetSubjectDistanceRange returns the distance range of the subject.
+func (h *Helper) GetSubjectDistanceRange() (SubjectDistanceRange, error) {
+	v, err := h.getShort(IfdExif, EXIF_TAG_SUBJECT_DISTANCE_RANGE)
+	return SubjectDistanceRange(v), err
+}
+
+// GetColorSpace returns the color space.
+func (h *Helper) GetColorSpace() (ColorSpace, error) {
+	v, err := h.getShort(IfdExif, EXIF_TAG_COLOR_SPACE)
+	return ColorSpace(v), err
+}
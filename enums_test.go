@@ -0,0 +1,81 @@
+package exif
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumStrings(t *testing.T) {
+	assert.Equal(t, "Aperture priority", ExposureProgramAperturePriority.String())
+	assert.Equal(t, "Center-weighted average", MeteringModeCenterWeightedAverage.String())
+	assert.Equal(t, "D65", LightSourceD65.String())
+	assert.Equal(t, "Manual white balance", WhiteBalanceManual.String())
+	assert.Equal(t, "Night scene", SceneCaptureTypeNightScene.String())
+	assert.Equal(t, "Trilinear sensor", SensingMethodTrilinear.String())
+	assert.Equal(t, "Custom process", CustomRenderedCustom.String())
+	assert.Equal(t, "High gain down", GainControlHighGainDown.String())
+	assert.Equal(t, "Hard", ContrastHard.String())
+	assert.Equal(t, "Low saturation", SaturationLow.String())
+	assert.Equal(t, "Soft", SharpnessSoft.String())
+	assert.Equal(t, "Close view", SubjectDistanceRangeClose.String())
+	assert.Equal(t, "Uncalibrated", ColorSpaceUncalibrated.String())
+	assert.Equal(t, "MeteringMode(7)", MeteringMode(7).String())
+}
+
+func TestFlash(t *testing.T) {
+	cases := map[uint16]struct {
+		flash Flash
+		text  string
+	}{
+		0x00: {Flash{}, "Flash did not fire"},
+		0x10: {Flash{Mode: FlashModeSuppressed}, "Flash did not fire, compulsory flash mode"},
+		0x19: {Flash{Fired: true, Mode: FlashModeAuto}, "Flash fired, auto mode"},
+		0x20: {Flash{NoFunction: true}, "No flash function"},
+		0x4f: {
+			Flash{Fired: true, Return: FlashReturnDetected, Mode: FlashModeCompulsory, RedEyeReduction: true},
+			"Flash fired, compulsory flash mode, red-eye reduction mode, return light detected",
+		},
+		0x5d: {
+			Flash{Fired: true, Return: FlashReturnNotDetected, Mode: FlashModeAuto, RedEyeReduction: true},
+			"Flash fired, auto mode, red-eye reduction mode, return light not detected",
+		},
+	}
+	for v, c := range cases {
+		assert.Equal(t, c.flash, NewFlash(v))
+		assert.Equal(t, v, c.flash.Value())
+		assert.Equal(t, c.text, c.flash.String())
+	}
+}
+
+func TestEnumHelpers(t *testing.T) {
+	data := New()
+	h := NewHelper(data)
+	_, err := h.GetFlash()
+	assert.True(t, errors.Is(err, ErrNotFoundEntry))
+
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_FLASH, uint16(0x19)))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_LIGHT_SOURCE, uint16(LightSourceCloudyWeather)))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_COLOR_SPACE, uint16(ColorSpaceSRGB)))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_SHARPNESS, uint16(SharpnessHard)))
+
+	flash, err := h.GetFlash()
+	require.NoError(t, err)
+	assert.Equal(t, Flash{Fired: true, Mode: FlashModeAuto}, flash)
+	light, err := h.GetLightSource()
+	require.NoError(t, err)
+	assert.Equal(t, LightSourceCloudyWeather, light)
+	space, err := h.GetColorSpace()
+	require.NoError(t, err)
+	assert.Equal(t, ColorSpaceSRGB, space)
+	sharpness, err := h.GetSharpness()
+	require.NoError(t, err)
+	assert.Equal(t, SharpnessHard, sharpness)
+
+	x, err := h.GetExposure()
+	require.NoError(t, err)
+	assert.Equal(t, flash, x.Flash)
+	assert.True(t, x.HasFlash)
+}
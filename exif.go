@@ -22,21 +22,14 @@
 // Package exif provides bindings for libexif.
 package exif
 
-/*
-#include <stdlib.h>
-#include <libexif/exif-data.h>
-#include <libexif/exif-loader.h>
-#include <libexif/exif-content.h>
-#include <libexif/exif-byte-order.h>
-*/
-import "C"
-
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
-	"fmt"
+	"io"
+	"os"
 	"runtime"
-	"unsafe"
+	"sort"
 )
 
 // Error messages.
@@ -47,15 +40,70 @@ var (
 
 // Data stores the EXIF tags of a file.
 type Data struct {
-	exifLoader *C.ExifLoader
+	exifLoader *exifLoader
 	Raw        map[IfdTag]Entry
 	Order      binary.ByteOrder
+	thumbnail  []byte
+	rawExif    []byte
+	// exifOffset is the position of rawExif in the data read.
+	exifOffset int
+	// ifds describes the IFDs read, see Ifds.
+	ifds []IfdInfo
+
+	// Entries lists the entries IFD by IFD, in the order of the file,
+	// including the tags repeated in an IFD. Raw indexes the first
+	// occurrence of each tag, which is the one Save writes. Set, SetEntry
+	// and Delete keep both in sync.
+	Entries []Entry
+
+	// Tags maps the title of each tag to its value as they were read from
+	// the file, see Entry.Formatted.
+	Tags map[string]string
+
+	// GPSPrecision is the denominator of the rationals written by
+	// SetLocation and SetGPSInfo, DefaultGPSPrecision when 0.
+	GPSPrecision uint32
+
+	// Diagnostics lists the messages reported while reading the data and
+	// fixing it. With cgo, these are the messages libexif logs, most of them
+	// LogDebug.
+	Diagnostics []Diagnostic
+
+	// Options changes how Open, Write and Parse load the data, and how Save
+	// writes the maker note.
+	Options ReadOptions
+}
+
+// ReadOptions toggles the options libexif applies when loading. The zero
+// value keeps the libexif defaults.
+type ReadOptions struct {
+	// KeepUnknownTags keeps the entries whose tag is not known in their IFD,
+	// which libexif drops by default. The sensitivity tags of EXIF 2.3 read
+	// by Helper.GetExposure are always kept.
+	KeepUnknownTags bool
+
+	// NoFix loads the entries as they are recorded. By default libexif
+	// follows the specification, see Fix. Without cgo, entries are never
+	// fixed.
+	NoFix bool
+
+	// DontChangeMakerNote makes Save write the maker note byte for byte. By
+	// default, libexif rewrites the maker notes it understands so that the
+	// offsets they record still hold once the maker note moves. A maker note
+	// changed after reading, or read without cgo, is always written as is.
+	DontChangeMakerNote bool
+
+	// Strict makes reading fail with ErrCorruptData or ErrNoMemory when a
+	// Diagnostic reports corrupt data or a failed allocation. Diagnostics
+	// is set all the same.
+	Strict bool
 }
 
 // New creates and returns a new exif.Data object.
 func New() *Data {
 	data := &Data{
-		Raw: make(map[IfdTag]Entry),
+		Raw:  make(map[IfdTag]Entry),
+		Tags: make(map[string]string),
 	}
 	return data
 }
@@ -69,82 +117,167 @@ func Read(file string) (*Data, error) {
 	return data, nil
 }
 
-// Open opens a file path and loads its EXIF data.
-func (d *Data) Open(file string) error {
-	cfile := C.CString(file)
-	defer C.free(unsafe.Pointer(cfile))
+// ReadWithOptions reads EXIF data from a file, loading it with opts.
+func ReadWithOptions(file string, opts ReadOptions) (*Data, error) {
+	data := New()
+	data.Options = opts
+	if err := data.Open(file); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
 
-	exifData := C.exif_data_new_from_file(cfile)
+// readChunkSize is the size of the reads done by ReadFrom.
+const readChunkSize = 4096
 
-	if exifData == nil {
-		return ErrNoExifData
+// ReadFrom reads EXIF data from r. It stops reading as soon as the EXIF block
+// is complete, and returns ErrNoExifData when r has none.
+func ReadFrom(r io.Reader) (*Data, error) {
+	return ReadFromWithOptions(r, ReadOptions{})
+}
+
+// ReadFromWithOptions reads EXIF data from r like ReadFrom, loading it with
+// opts.
+func ReadFromWithOptions(r io.Reader, opts ReadOptions) (*Data, error) {
+	data := New()
+	data.Options = opts
+	if err := data.readFrom(r); err != nil {
+		return nil, err
 	}
-	defer C.exif_data_unref(exifData)
+	return data, nil
+}
 
-	return d.parseRaw(exifData)
+// ReadBytes reads EXIF data from the content of a file, or from an EXIF
+// block starting with the "Exif\x00\x00" header.
+func ReadBytes(b []byte) (*Data, error) {
+	return ReadBytesWithOptions(b, ReadOptions{})
 }
 
-func (d *Data) parseRaw(ed *C.ExifData) error {
-	var raw []byte
-	var tag uint16 = 0
-	var ifd uint16 = 0
+// ReadBytesWithOptions reads EXIF data from b like ReadBytes, loading it with
+// opts.
+func ReadBytesWithOptions(b []byte, opts ReadOptions) (*Data, error) {
+	data := New()
+	data.Options = opts
+	if bytes.HasPrefix(b, exifHeader) {
+		// The loader only accepts EXIF blocks inside a JPEG segment.
+		if err := data.loadBlock(b); err != nil {
+			return nil, err
+		}
+		if err := data.strictError(); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
 
-	order := C.exif_data_get_byte_order(ed)
-	if order == C.EXIF_BYTE_ORDER_MOTOROLA {
-		d.Order = binary.BigEndian
-	} else if order == C.EXIF_BYTE_ORDER_INTEL {
-		d.Order = binary.LittleEndian
+	if err := data.readFrom(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Open opens a file path and loads its EXIF data.
+func (d *Data) Open(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
 	}
+	defer f.Close()
+
+	return d.readFrom(f)
+}
 
-	for i:=0; i!= C.EXIF_IFD_COUNT; i++ {
-		content := (*ed).ifd[i]
-		length := int((*content).count)
-		var pEntries **C.ExifEntry = (*content).entries
+func (d *Data) readFrom(r io.Reader) error {
+	loader, err := newExifLoader()
+	if err != nil {
+		return err
+	}
+	defer loader.free()
 
-		if pEntries == nil {
-			continue
+	buf := make([]byte, readChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 && !loader.write(buf[:n]) {
+			break
 		}
-		sEntries := (*[1<<30] *C.ExifEntry)(unsafe.Pointer(pEntries))[:length:length]
-		for _, pEntry := range sEntries {
-			entry := *pEntry
-			tag = uint16(C.uint16_t(entry.tag))
-
-			if pEntry == nil {
-				ifd = uint16(C.uint16_t(C.EXIF_IFD_COUNT))
-			} else {
-				ifd = uint16(C.uint16_t(C.exif_content_get_ifd(entry.parent)))
-			}
-			key := NewIfdTag(ifd, tag)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := d.load(loader); err != nil {
+		return err
+	}
+	return d.strictError()
+}
 
-			if entry.data != nil && entry.size != 0 {
-				raw = C.GoBytes(unsafe.Pointer(entry.data), C.int(entry.size))
+// setEntries lists the entries of Raw in the order r read them, along with
+// the repeated tags Raw cannot hold, and adds the IFDs only r loads to Raw.
+// The entries the parser added are listed after the others of their IFD. r
+// may be nil.
+func (d *Data) setEntries(r *tiffReader) {
+	d.Entries = nil
+	d.ifds = nil
+	listed := make(map[IfdTag]bool)
+	base := d.exifOffset + len(exifHeader)
+
+	last := IfdMaxCount
+	if r != nil {
+		d.ifds = r.ifds
+		last = r.nextIfd
+	}
+	for ifd := Ifd0; ifd < last; ifd++ {
+		if r != nil {
+			for _, e := range r.entries[ifd] {
+				key := NewIfdTag(uint16(ifd), uint16(e.Tag))
+				first, ok := d.Raw[key]
+				if !ok && (ifd >= IfdMaxCount || keptTags[key]) {
+					// libexif does not load these IFDs and tags.
+					first, ok = e, true
+				}
+				if !ok {
+					// Removed by the parser.
+					continue
+				}
+				e.Offset += base
+				if !listed[key] {
+					listed[key] = true
+					first.Offset, first.Length = e.Offset, e.Length
+					d.Raw[key] = first
+					e = first
+				}
+				d.Entries = append(d.Entries, e)
 			}
+		}
 
-			d.Raw[key] = Entry{
-				Ifd: Ifd(ifd),
-				Tag: Tag(tag),
-				Format: EntryFormat(int(C.int(entry.format))),
-				Components: int(C.ulong(entry.components)),
-				Raw: raw,
-				order: d.Order,
+		var added []Entry
+		for key, e := range d.Raw {
+			if e.Ifd == ifd && !listed[key] {
+				added = append(added, e)
 			}
 		}
+		sort.Slice(added, func(i, j int) bool {
+			return added[i].Tag < added[j].Tag
+		})
+		d.Entries = append(d.Entries, added...)
 	}
-
-	return nil
 }
 
 // Write writes bytes to the exif loader. Sends ErrFoundExifInData error when
 // enough bytes have been sent.
 func (d *Data) Write(p []byte) (n int, err error) {
 	if d.exifLoader == nil {
-		d.exifLoader = C.exif_loader_new()
+		loader, err := newExifLoader()
+		if err != nil {
+			return 0, err
+		}
+		d.exifLoader = loader
 		runtime.SetFinalizer(d, (*Data).cleanup)
 	}
 
-	res := C.exif_loader_write(d.exifLoader, (*C.uchar)(unsafe.Pointer(&p[0])), C.uint(len(p)))
-
-	if res == 1 {
+	if d.exifLoader.write(p) {
 		return len(p), nil
 	}
 	return len(p), ErrFoundExifInData
@@ -154,21 +287,19 @@ func (d *Data) Write(p []byte) (n int, err error) {
 func (d *Data) Parse() error {
 	defer d.cleanup()
 
-	exifData := C.exif_loader_get_data(d.exifLoader)
-	if exifData == nil {
-		return fmt.Errorf(ErrNoExifData.Error(), "")
+	if d.exifLoader == nil {
+		return ErrNoExifData
 	}
 
-	defer func() {
-		C.exif_data_unref(exifData)
-	}()
-
-	return d.parseRaw(exifData)
+	if err := d.load(d.exifLoader); err != nil {
+		return err
+	}
+	return d.strictError()
 }
 
 func (d *Data) cleanup() {
 	if d.exifLoader != nil {
-		C.exif_loader_unref(d.exifLoader)
+		d.exifLoader.free()
 		d.exifLoader = nil
 	}
 }
@@ -1,3 +1,5 @@
+//go:build cgo
+
 /*
   Copyright (c) 2012-2013 José Carlos Nieto, https://menteslibres.net/xiam
 
@@ -21,15 +23,19 @@
   WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
 */
 
+#include <stdarg.h>
 #include <stdio.h>
 #include <stdlib.h>
 #include <string.h>
 
 #include <libexif/exif-data.h>
+#include <libexif/exif-log.h>
 
 #include "_cgo/types.h"
+#include "_cgo_export.h"
 
 #define EXIF_VALUE_MAXLEN 256
+#define EXIF_LOG_MAXLEN 1024
 
 void import_entry(ExifEntry*, void*);
 void import_ifds(ExifContent*, void*);
@@ -38,18 +44,34 @@ void push_exif_value(exif_stack_t*, exif_value_t*);
 exif_value_t* pop_exif_value(exif_stack_t *);
 void free_exif_value(exif_value_t* n);
 exif_stack_t* exif_dump(ExifData *);
+ExifLog* exif_log_new_go(uintptr_t);
 
 void import_entry(ExifEntry* entry, void* user_data) {
   exif_value_t* value;
   unsigned char* entry_data;
   char exif_text[EXIF_VALUE_MAXLEN];
 
+  const char* title;
+  const char* text;
+
   value = new_exif_value();
+  if (value == NULL) {
+    return;
+  }
 
   ExifIfd ifd = exif_entry_get_ifd(entry);
 
-  strncpy(value->name, exif_tag_get_title_in_ifd(entry->tag, ifd), EXIF_VALUE_MAXLEN);
-  strncpy(value->value, exif_entry_get_value(entry, exif_text, EXIF_VALUE_MAXLEN), EXIF_VALUE_MAXLEN);
+  title = exif_tag_get_title_in_ifd(entry->tag, ifd);
+  if (title == NULL) {
+    snprintf(value->name, EXIF_VALUE_MAXLEN, "0x%04x", entry->tag);
+  } else {
+    strncpy(value->name, title, EXIF_VALUE_MAXLEN - 1);
+  }
+
+  text = exif_entry_get_value(entry, exif_text, EXIF_VALUE_MAXLEN);
+  if (text != NULL) {
+    strncpy(value->value, text, EXIF_VALUE_MAXLEN - 1);
+  }
 
   push_exif_value(user_data, value);
 }
@@ -66,11 +88,14 @@ exif_value_t* new_exif_value() {
     return NULL;
   }
 
-  n->name = (char *)malloc(sizeof(char)*EXIF_VALUE_MAXLEN);
-  n->value = (char *)malloc(sizeof(char)*EXIF_VALUE_MAXLEN);
+  n->name = (char *)calloc(EXIF_VALUE_MAXLEN, sizeof(char));
+  n->value = (char *)calloc(EXIF_VALUE_MAXLEN, sizeof(char));
+
+  if (n->name == NULL || n->value == NULL) {
+    free_exif_value(n);
+    return NULL;
+  }
 
-  n->name[0]  = '\0';
-  n->value[0] = '\0';
   n->prev     = NULL;
   return n;
 }
@@ -107,3 +132,22 @@ exif_stack_t* exif_dump(ExifData* data) {
 
   return user_data;
 }
+
+static void exif_log_go(ExifLog* log, ExifLogCode code, const char* domain,
+                        const char* format, va_list args, void* data) {
+  char message[EXIF_LOG_MAXLEN];
+
+  vsnprintf(message, EXIF_LOG_MAXLEN, format, args);
+  goExifLog((uintptr_t)data, code, (char*)domain, message);
+}
+
+ExifLog* exif_log_new_go(uintptr_t handle) {
+  ExifLog* log;
+
+  log = exif_log_new();
+  if (log != NULL) {
+    exif_log_set_func(log, exif_log_go, (void*)handle);
+  }
+
+  return log;
+}
@@ -0,0 +1,268 @@
+//go:build cgo
+
+package exif
+
+/*
+#include <stdlib.h>
+#include <libexif/exif-data.h>
+#include <libexif/exif-loader.h>
+#include <libexif/exif-content.h>
+#include <libexif/exif-byte-order.h>
+
+#include "_cgo/types.h"
+
+exif_stack_t* exif_dump(ExifData *);
+exif_value_t* pop_exif_value(exif_stack_t *);
+void free_exif_value(exif_value_t* n);
+*/
+import "C"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"unsafe"
+)
+
+// exifLoader wraps the libexif loader.
+type exifLoader struct {
+	loader *C.ExifLoader
+	// scan locates the block in the stream, which libexif does not tell.
+	scan exifScanner
+}
+
+func newExifLoader() (*exifLoader, error) {
+	loader := C.exif_loader_new()
+	if loader == nil {
+		return nil, ErrNoMemory
+	}
+	return &exifLoader{loader: loader}, nil
+}
+
+// write feeds p to the loader, and reports whether it wants more data.
+func (l *exifLoader) write(p []byte) bool {
+	if len(p) == 0 {
+		return true
+	}
+	l.scan.write(p)
+	return C.exif_loader_write(l.loader, (*C.uchar)(unsafe.Pointer(&p[0])), C.uint(len(p))) == 1
+}
+
+func (l *exifLoader) free() {
+	C.exif_loader_unref(l.loader)
+}
+
+// load parses the EXIF data collected by loader.
+func (d *Data) load(loader *exifLoader) error {
+	var buf *C.uchar
+	var size C.uint
+	C.exif_loader_get_buf(loader.loader, &buf, &size)
+	if buf == nil || size == 0 {
+		return ErrNoExifData
+	}
+
+	// libexif also finds blocks outside of a JPEG stream, whose position in
+	// the file is not known.
+	b := loader.scan.exif
+	if b == nil || uint(size) < uint(len(b)) {
+		return ErrNoExifData
+	}
+
+	// Keep the raw data around, libexif does not load everything the
+	// entries point to. It reads the 2 bytes following the segment.
+	d.rawExif = C.GoBytes(unsafe.Pointer(buf), C.int(len(b)))
+	if !bytes.Equal(d.rawExif, b) {
+		d.rawExif = nil
+		return ErrNoExifData
+	}
+	d.exifOffset = loader.scan.exifOffset
+
+	return d.loadData(buf, size)
+}
+
+// loadData parses buf with libexif, collecting the messages it logs.
+func (d *Data) loadData(buf *C.uchar, size C.uint) error {
+	// libexif loads nothing, without failing, when the TIFF header is wrong.
+	if _, err := newTiffReader(d.rawExif, false); err != nil {
+		return err
+	}
+
+	log, err := newExifLog()
+	if err != nil {
+		return err
+	}
+	defer log.free()
+
+	ed, err := newExifData(d.Options)
+	if err != nil {
+		return err
+	}
+	defer C.exif_data_unref(ed)
+
+	log.attach(ed)
+	C.exif_data_load_data(ed, buf, size)
+	err = d.parseRaw(ed)
+	d.Diagnostics = log.diagnostics
+	return err
+}
+
+// newExifData creates an empty ExifData set up with opts. The caller must
+// release it with exif_data_unref.
+func newExifData(opts ReadOptions) (*C.ExifData, error) {
+	ed := C.exif_data_new()
+	if ed == nil {
+		return nil, ErrNoMemory
+	}
+	if opts.KeepUnknownTags {
+		C.exif_data_unset_option(ed, C.EXIF_DATA_OPTION_IGNORE_UNKNOWN_TAGS)
+	}
+	if opts.NoFix {
+		C.exif_data_unset_option(ed, C.EXIF_DATA_OPTION_FOLLOW_SPECIFICATION)
+	}
+	if opts.DontChangeMakerNote {
+		C.exif_data_set_option(ed, C.EXIF_DATA_OPTION_DONT_CHANGE_MAKER_NOTE)
+	}
+	return ed, nil
+}
+
+// loadBlock parses an EXIF block starting with the "Exif\x00\x00" header.
+func (d *Data) loadBlock(b []byte) error {
+	d.rawExif = append([]byte(nil), b...)
+	return d.loadData((*C.uchar)(unsafe.Pointer(&d.rawExif[0])), C.uint(len(d.rawExif)))
+}
+
+func (d *Data) parseRaw(ed *C.ExifData) error {
+	order := C.exif_data_get_byte_order(ed)
+	if order == C.EXIF_BYTE_ORDER_MOTOROLA {
+		d.Order = binary.BigEndian
+	} else if order == C.EXIF_BYTE_ORDER_INTEL {
+		d.Order = binary.LittleEndian
+	}
+
+	if ed.data != nil && ed.size != 0 {
+		d.thumbnail = C.GoBytes(unsafe.Pointer(ed.data), C.int(ed.size))
+	}
+
+	d.readContents(ed, d.Raw)
+
+	// libexif drops the repeated tags, they are read again from the block.
+	r, _ := readExifBlock(d.rawExif, !d.Options.KeepUnknownTags)
+	d.setEntries(r)
+
+	d.parseTags(ed)
+
+	return nil
+}
+
+// readContents stores the entries of ed in entries.
+func (d *Data) readContents(ed *C.ExifData, entries map[IfdTag]Entry) {
+	var tag uint16 = 0
+	var ifd uint16 = 0
+
+	for i:=0; i!= C.EXIF_IFD_COUNT; i++ {
+		content := (*ed).ifd[i]
+		length := int((*content).count)
+		var pEntries **C.ExifEntry = (*content).entries
+
+		if pEntries == nil {
+			continue
+		}
+		sEntries := (*[1<<30] *C.ExifEntry)(unsafe.Pointer(pEntries))[:length:length]
+		for _, pEntry := range sEntries {
+			entry := *pEntry
+			tag = uint16(C.uint16_t(entry.tag))
+
+			if pEntry == nil {
+				ifd = uint16(C.uint16_t(C.EXIF_IFD_COUNT))
+			} else {
+				ifd = uint16(C.uint16_t(C.exif_content_get_ifd(entry.parent)))
+			}
+			key := NewIfdTag(ifd, tag)
+
+			var raw []byte
+			if entry.data != nil && entry.size != 0 {
+				raw = C.GoBytes(unsafe.Pointer(entry.data), C.int(entry.size))
+			}
+
+			entries[key] = Entry{
+				Ifd: Ifd(ifd),
+				Tag: Tag(tag),
+				Format: EntryFormat(int(C.int(entry.format))),
+				Components: int(C.ulong(entry.components)),
+				Raw: raw,
+				order: d.Order,
+			}
+		}
+	}
+}
+
+// fixedEntries returns the entries of the fixed IFDs once fixed by libexif,
+// adding the messages it logs to Diagnostics.
+func (d *Data) fixedEntries() (map[IfdTag]Entry, error) {
+	log, err := newExifLog()
+	if err != nil {
+		return nil, err
+	}
+	defer log.free()
+
+	ed, err := d.buildExifData()
+	if err != nil {
+		return nil, err
+	}
+	defer C.exif_data_unref(ed)
+
+	log.attach(ed)
+	C.exif_data_fix(ed)
+	d.Diagnostics = append(d.Diagnostics, log.diagnostics...)
+
+	fixed := make(map[IfdTag]Entry)
+	d.readContents(ed, fixed)
+	return fixed, nil
+}
+
+// parseTags fills Tags with the values formatted by libexif. When a title is
+// used in several IFDs, the value of the first IFD wins.
+func (d *Data) parseTags(ed *C.ExifData) {
+	if d.Tags == nil {
+		d.Tags = make(map[string]string)
+	}
+
+	values := C.exif_dump(ed)
+	defer C.free(unsafe.Pointer(values))
+
+	for {
+		value := C.pop_exif_value(values)
+		if value == nil {
+			break
+		}
+		name := strings.TrimSpace(C.GoString((*value).name))
+		d.Tags[name] = strings.TrimSpace(C.GoString((*value).value))
+		C.free_exif_value(value)
+	}
+}
+
+// Formatted returns the value of the entry as formatted by libexif, such as
+// "1/125 sec." for an exposure time.
+func (e *Entry) Formatted() string {
+	if e.Ifd >= IfdMaxCount {
+		return ""
+	}
+
+	ed := C.exif_data_new()
+	if ed == nil {
+		return ""
+	}
+	defer C.exif_data_unref(ed)
+
+	setByteOrder(ed, e.byteOrder())
+	ce, err := addExifEntry(ed.ifd[e.Ifd], e)
+	if err != nil {
+		return ""
+	}
+
+	var buf [256]C.char
+	if C.exif_entry_get_value(ce, &buf[0], C.uint(len(buf))) == nil {
+		return ""
+	}
+	return strings.TrimSpace(C.GoString(&buf[0]))
+}
@@ -0,0 +1,23 @@
+//go:build cgo
+
+package exif
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormattedLibexif(t *testing.T) {
+	data, err := Read("_examples/resources/test.jpg")
+	require.NoError(t, err)
+
+	fnumber := NewHelper(data).GetEntry(uint16(IfdExif), uint16(EXIF_TAG_FNUMBER))
+	require.NotNil(t, fnumber)
+	assert.Equal(t, "f/7.0", fnumber.Formatted())
+}
+
+// libexifFixes reports whether Read changes the entries to follow the
+// specification, see explainDiff.
+const libexifFixes = true
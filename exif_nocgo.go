@@ -0,0 +1,114 @@
+//go:build !cgo
+
+package exif
+
+import (
+	"fmt"
+	"strings"
+)
+
+// exifLoader finds the EXIF block of a JPEG stream.
+type exifLoader struct {
+	exifScanner
+}
+
+func newExifLoader() (*exifLoader, error) {
+	return &exifLoader{}, nil
+}
+
+func (l *exifLoader) free() {
+	l.buf = nil
+}
+
+// load parses the EXIF data collected by loader.
+func (d *Data) load(loader *exifLoader) error {
+	if loader.exif == nil {
+		return ErrNoExifData
+	}
+	d.exifOffset = loader.exifOffset
+	return d.loadBlock(loader.exif)
+}
+
+// loadBlock parses an EXIF block starting with the "Exif\x00\x00" header.
+func (d *Data) loadBlock(b []byte) error {
+	r, err := readExifBlock(b, !d.Options.KeepUnknownTags)
+	if err != nil {
+		return err
+	}
+
+	d.rawExif = append([]byte(nil), b...)
+	d.Order = r.order
+	d.Diagnostics = r.diagnostics
+	if len(r.thumbnail) != 0 {
+		d.thumbnail = r.thumbnail
+	}
+
+	if d.Tags == nil {
+		d.Tags = make(map[string]string)
+	}
+	for ifd := Ifd0; ifd < IfdMaxCount; ifd++ {
+		for _, e := range r.entries[ifd] {
+			key := NewIfdTag(uint16(ifd), uint16(e.Tag))
+			if _, ok := d.Raw[key]; !ok {
+				d.Raw[key] = e
+			}
+		}
+	}
+	d.setEntries(r)
+
+	// When a title is used several times, the value of the first entry wins.
+	for i := len(d.Entries) - 1; i >= 0; i-- {
+		e := &d.Entries[i]
+		if e.Ifd >= IfdMaxCount {
+			// As with libexif, the other IFDs are left out.
+			continue
+		}
+		title := e.Tag.Title(e.Ifd)
+		if title == "" {
+			title = fmt.Sprintf("0x%04x", uint16(e.Tag))
+		}
+		d.Tags[title] = e.Formatted()
+	}
+
+	return nil
+}
+
+// fixedEntries needs the libexif specification tables, see Fix.
+func (d *Data) fixedEntries() (map[IfdTag]Entry, error) {
+	return nil, ErrUnsupportedFix
+}
+
+// Formatted returns the value of the entry as text. Without cgo, values are
+// not interpreted the way libexif does: numbers are listed as they are
+// recorded, separated by commas.
+func (e *Entry) Formatted() string {
+	v, err := e.GetValue()
+	if err != nil {
+		return ""
+	}
+
+	var parts []string
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(strings.TrimRight(v, "\x00"))
+	case []byte:
+		if e.Format == FormatUndefined {
+			return fmt.Sprintf("%d bytes undefined data", len(v))
+		}
+		for _, b := range v {
+			parts = append(parts, fmt.Sprint(b))
+		}
+	case []UnsignedRational:
+		for i := range v {
+			parts = append(parts, v[i].String())
+		}
+	case []SignedRational:
+		for i := range v {
+			parts = append(parts, v[i].String())
+		}
+	default:
+		s := fmt.Sprint(v)
+		return strings.Join(strings.Fields(strings.Trim(s, "[]")), ", ")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,49 @@
+//go:build !cgo
+
+package exif
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormattedNoCgo(t *testing.T) {
+	data, err := Read("_examples/resources/test.jpg")
+	require.NoError(t, err)
+
+	h := NewHelper(data)
+	fnumber := h.GetEntry(uint16(IfdExif), uint16(EXIF_TAG_FNUMBER))
+	require.NotNil(t, fnumber)
+	assert.Equal(t, "70/10", fnumber.Formatted())
+
+	resolution := h.GetEntry(uint16(Ifd0), uint16(EXIF_TAG_RESOLUTION_UNIT))
+	require.NotNil(t, resolution)
+	assert.Equal(t, "2", resolution.Formatted())
+}
+
+func TestLoaderChunks(t *testing.T) {
+	want, err := Read("_examples/resources/testlocation.jpg")
+	require.NoError(t, err)
+	src, err := os.ReadFile("_examples/resources/testlocation.jpg")
+	require.NoError(t, err)
+
+	// Feed the loader one byte at a time.
+	loader, err := newExifLoader()
+	require.NoError(t, err)
+	n := 0
+	for n < len(src) && loader.write(src[n:n+1]) {
+		n++
+	}
+	assert.Less(t, n, len(src))
+
+	data := New()
+	require.NoError(t, data.load(loader))
+	assert.Equal(t, want.Raw, data.Raw)
+}
+
+// libexifFixes reports whether Read changes the entries to follow the
+// specification, see explainDiff.
+const libexifFixes = false
@@ -1,11 +1,14 @@
 package exif
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -73,4 +76,275 @@ func TestReadLocation(t *testing.T) {
 	loc, err := helper.GetLocation()
 	require.Nil(t, err)
 	println(loc.String())
-}
\ No newline at end of file
+}
+
+func TestSaveFile(t *testing.T) {
+	src, err := os.ReadFile("_examples/resources/test.jpg")
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "test.jpg")
+	require.NoError(t, os.WriteFile(file, src, 0644))
+
+	data, err := Read(file)
+	require.NoError(t, err)
+
+	key := NewIfdTag(uint16(Ifd0), uint16(EXIF_TAG_MAKE))
+	entry := data.Raw[key]
+	require.NotEmpty(t, entry.Raw)
+	entry.Raw = append([]byte("ACME"), 0)
+	entry.Components = len(entry.Raw)
+	data.Raw[key] = entry
+
+	require.NoError(t, data.SaveFile(file))
+
+	saved, err := Read(file)
+	require.NoError(t, err)
+	assert.Equal(t, data.Order, saved.Order)
+	assert.Equal(t, data.thumbnail, saved.thumbnail)
+	require.Equal(t, len(data.Raw), len(saved.Raw))
+	for key, val := range data.Raw {
+		got := saved.Raw[key]
+		// The values move when the block is written again.
+		val.Offset, val.Length = got.Offset, got.Length
+		assert.Equal(t, val, got, key.String())
+	}
+
+	// Everything after the EXIF segment must be left untouched.
+	dst, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, src[exifSegmentEnd(src):], dst[exifSegmentEnd(dst):])
+}
+
+func exifSegmentEnd(b []byte) int {
+	for pos := 2; pos+4 <= len(b); {
+		end := pos + 2 + (int(b[pos+2])<<8 | int(b[pos+3]))
+		if b[pos+1] == 0xe1 && bytes.HasPrefix(b[pos+4:], []byte("Exif")) {
+			return end
+		}
+		pos = end
+	}
+	return 0
+}
+
+func TestTags(t *testing.T) {
+	data, err := Read("_examples/resources/test.jpg")
+	require.NoError(t, err)
+
+	assert.Equal(t, "FUJIFILM", data.Tags["Manufacturer"])
+	assert.Equal(t, "MX-1700ZOOM", data.Tags["Model"])
+	assert.Equal(t, "2000:09:02 14:30:10", data.Tags["Date and Time (Original)"])
+
+	helper := NewHelper(data)
+	fnumber := helper.GetEntry(uint16(IfdExif), uint16(EXIF_TAG_FNUMBER))
+	require.NotNil(t, fnumber)
+	assert.Equal(t, data.Tags["F-Number"], fnumber.Formatted())
+
+	e, err := data.NewAsciiEntry(Ifd0, EXIF_TAG_MAKE, "ACME")
+	require.NoError(t, err)
+	assert.Equal(t, "ACME", e.Formatted())
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestReadFromAndBytes(t *testing.T) {
+	src, err := os.ReadFile("_examples/resources/test.jpg")
+	require.NoError(t, err)
+	want, err := Read("_examples/resources/test.jpg")
+	require.NoError(t, err)
+
+	cr := &countingReader{r: bytes.NewReader(src)}
+	data, err := ReadFrom(cr)
+	require.NoError(t, err)
+	assert.Equal(t, want.Raw, data.Raw)
+	assert.Equal(t, want.Tags, data.Tags)
+	assert.Less(t, cr.n, len(src))
+
+	data, err = ReadBytes(src)
+	require.NoError(t, err)
+	assert.Equal(t, want.Raw, data.Raw)
+
+	block, err := want.marshal()
+	require.NoError(t, err)
+	data, err = ReadBytes(block)
+	require.NoError(t, err)
+	// The block is laid out again, only the values are the same.
+	key := NewIfdTag(uint16(Ifd0), uint16(EXIF_TAG_MAKE))
+	assert.Equal(t, want.Raw[key].Raw, data.Raw[key].Raw)
+
+	// libexif also accepts an Exif segment without the JPEG start marker.
+	segment := newTiffBytes(binary.LittleEndian).u16(1).short(EXIF_TAG_ORIENTATION, 1).u32(0).jpeg()[2:]
+	for _, b := range [][]byte{nil, []byte("not an image"), {0xff, 0xd8, 0xff, 0xd9}, segment} {
+		_, err = ReadBytes(b)
+		assert.Equal(t, ErrNoExifData, err)
+		_, err = ReadFrom(bytes.NewReader(b))
+		assert.Equal(t, ErrNoExifData, err)
+	}
+
+	// An Exif header followed by something else than TIFF data.
+	garbage := []byte("Exif\x00\x00not tiff data")
+	_, err = ReadBytes(garbage)
+	assert.Equal(t, ErrNoExifData, err)
+	jpg := []byte{0xff, 0xd8, 0xff, 0xe1, 0, byte(len(garbage) + 2)}
+	jpg = append(append(jpg, garbage...), 0xff, 0xd9)
+	_, err = ReadBytes(jpg)
+	assert.Equal(t, ErrNoExifData, err)
+
+	n, err := New().Write(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestEntries(t *testing.T) {
+	data, err := Read("_examples/resources/test.jpg")
+	require.NoError(t, err)
+
+	require.Len(t, data.Entries, len(data.Raw))
+	for i, e := range data.Entries {
+		assert.Equal(t, data.Raw[NewIfdTag(uint16(e.Ifd), uint16(e.Tag))], e)
+		if i > 0 {
+			assert.LessOrEqual(t, data.Entries[i-1].Ifd, e.Ifd)
+		}
+	}
+
+	// The entries read from the file come first, in the order of the file.
+	file, err := refWalk(data.rawExif)
+	require.NoError(t, err)
+	var want, got []Tag
+	for _, e := range file.Entries {
+		if _, ok := data.Raw[NewIfdTag(uint16(e.Ifd), uint16(e.Tag))]; ok && e.Ifd == IfdExif {
+			want = append(want, e.Tag)
+		}
+	}
+	for _, e := range data.Entries {
+		if e.Ifd == IfdExif {
+			got = append(got, e.Tag)
+		}
+	}
+	require.NotEmpty(t, want)
+	assert.Equal(t, want, got[:len(want)])
+
+	// Repeated tags are kept, Raw holds the first one.
+	data, err = ReadBytes(newTiffBytes(binary.LittleEndian).u16(3).
+		short(EXIF_TAG_ORIENTATION, 1).
+		short(EXIF_TAG_RESOLUTION_UNIT, 2).
+		short(EXIF_TAG_ORIENTATION, 8).u32(0).jpeg())
+	require.NoError(t, err)
+
+	orientations := func() (out [][]byte) {
+		for _, e := range data.Entries {
+			if e.Ifd == Ifd0 && e.Tag == EXIF_TAG_ORIENTATION {
+				out = append(out, e.Raw)
+			}
+		}
+		return out
+	}
+	assert.Equal(t, [][]byte{{1, 0}, {8, 0}}, orientations())
+	assert.Equal(t, []byte{1, 0}, data.Raw[NewIfdTag(uint16(Ifd0), uint16(EXIF_TAG_ORIENTATION))].Raw)
+
+	require.NoError(t, data.Set(Ifd0, EXIF_TAG_ORIENTATION, uint16(3)))
+	assert.Equal(t, [][]byte{{3, 0}, {8, 0}}, orientations())
+
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_EXPOSURE_PROGRAM, uint16(2)))
+	last := data.Entries[len(data.Entries)-1]
+	assert.Equal(t, EXIF_TAG_EXPOSURE_PROGRAM, last.Tag)
+	_, ok := data.Raw[NewIfdTag(uint16(Ifd0), uint16(EXIF_TAG_EXIF_IFD_POINTER))]
+	assert.True(t, ok)
+
+	data.Delete(Ifd0, EXIF_TAG_ORIENTATION)
+	assert.Empty(t, orientations())
+	data.Delete(IfdExif, EXIF_TAG_EXPOSURE_PROGRAM)
+	assert.Len(t, data.Entries, len(data.Raw))
+}
+
+// parseWith streams src to a Data loading it with opts.
+func parseWith(t *testing.T, opts ReadOptions, src []byte) *Data {
+	data := New()
+	data.Options = opts
+	_, err := data.Write(src)
+	if err != ErrFoundExifInData {
+		require.NoError(t, err)
+	}
+	require.NoError(t, data.Parse())
+	return data
+}
+
+func TestReadOptions(t *testing.T) {
+	unknown := Tag(0x9999)
+	src := newTiffBytes(binary.LittleEndian).u16(2).
+		short(EXIF_TAG_ORIENTATION, 1).
+		short(unknown, 7).u32(0).jpeg()
+	key := NewIfdTag(uint16(Ifd0), uint16(unknown))
+
+	data := parseWith(t, ReadOptions{}, src)
+	_, ok := data.Raw[key]
+	assert.False(t, ok)
+	assert.Equal(t, libexifFixes, len(data.Raw) > 1)
+
+	data = parseWith(t, ReadOptions{KeepUnknownTags: true, NoFix: true}, src)
+	assert.Len(t, data.Raw, 2)
+	assert.Equal(t, []byte{7, 0}, data.Raw[key].Raw)
+	assert.Equal(t, []Tag{EXIF_TAG_ORIENTATION, unknown}, []Tag{data.Entries[0].Tag, data.Entries[1].Tag})
+
+	// The options apply whatever the data is read from.
+	opts := ReadOptions{KeepUnknownTags: true, NoFix: true}
+	read := []func() (*Data, error){
+		func() (*Data, error) { return ReadFromWithOptions(bytes.NewReader(src), opts) },
+		func() (*Data, error) { return ReadBytesWithOptions(src, opts) },
+		func() (*Data, error) { return ReadBytesWithOptions(data.rawExif, opts) },
+	}
+	for i, f := range read {
+		data, err := f()
+		require.NoError(t, err, "%d", i)
+		assert.Len(t, data.Raw, 2, "%d", i)
+		assert.Equal(t, []byte{7, 0}, data.Raw[key].Raw, "%d", i)
+	}
+	data, err := ReadBytes(data.rawExif)
+	require.NoError(t, err)
+	assert.NotContains(t, data.Raw, key)
+
+	data, err = ReadWithOptions("_examples/resources/test.jpg", ReadOptions{NoFix: true})
+	require.NoError(t, err)
+	for _, e := range data.Entries {
+		assert.NotZero(t, e.Offset, e.String())
+	}
+
+	// The Canon maker note records the offset of its values from the TIFF
+	// header, libexif updates it when the maker note moves.
+	src = newTiffBytes(binary.LittleEndian).u16(2).
+		entry(EXIF_TAG_MAKE, FormatAscii, 6, 38).
+		entry(EXIF_TAG_EXIF_IFD_POINTER, FormatUnsignedLong, 1, 44).u32(0).
+		bytes([]byte("Canon\x00")...).
+		u16(1).entry(EXIF_TAG_MAKER_NOTE, FormatUndefined, 28, 62).u32(0).
+		u16(1).entry(0x0006, FormatAscii, 10, 80).u32(0).
+		bytes([]byte("IMG:TEST\x00\x00")...).jpeg()
+	note := NewIfdTag(uint16(IfdExif), uint16(EXIF_TAG_MAKER_NOTE))
+
+	for _, keep := range []bool{false, true} {
+		data = parseWith(t, ReadOptions{DontChangeMakerNote: keep}, src)
+		block, err := data.marshal()
+		require.NoError(t, err)
+		saved, err := ReadBytes(block)
+		require.NoError(t, err)
+
+		raw := saved.Raw[note].Raw
+		require.Len(t, raw, 28)
+		offset := 6 + int(binary.LittleEndian.Uint32(raw[10:]))
+		moved := offset+8 > len(block) || string(block[offset:offset+8]) != "IMG:TEST"
+		if keep || !libexifFixes {
+			assert.Equal(t, data.Raw[note].Raw, raw)
+		}
+		if libexifFixes {
+			assert.Equal(t, keep, moved)
+		}
+	}
+}
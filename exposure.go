@@ -0,0 +1,242 @@
+package exif
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+// isoSpeedOverflow is recorded in ISOSpeedRatings when the sensitivity does
+// not fit in a SHORT.
+const isoSpeedOverflow = 65535
+
+// Exposure gathers the settings a photo was taken with. Fields are 0 when
+// the image does not record them.
+type Exposure struct {
+	// ExposureTime is in seconds, ExposureTimeString formats it as "1/250"
+	// or "2.5".
+	ExposureTime       UnsignedRational
+	ExposureTimeString string
+	FNumber            float64
+
+	// ISO is the sensitivity, ISOValues lists every ISOSpeedRatings value.
+	// SensitivityType tells what ISO is measured as, and
+	// RecommendedExposureIndex is used when ISOSpeedRatings is missing or
+	// too small to hold the sensitivity.
+	ISO                      uint32
+	ISOValues                []uint16
+	SensitivityType          uint16
+	RecommendedExposureIndex uint32
+
+	// ExposureBias is in EV.
+	ExposureBias    float64
+	ExposureProgram ExposureProgram
+	MeteringMode    MeteringMode
+	Flash           Flash
+	HasFlash        bool
+
+	// FocalLength is in millimeters.
+	FocalLength       float64
+	FocalLengthIn35mm uint16
+}
+
+// GetExposure returns the exposure settings. When the exposure time, the
+// F-number or the sensitivity are missing, they are derived from the APEX
+// values: ShutterSpeedValue, ApertureValue and BrightnessValue.
+func (h *Helper) GetExposure() (*Exposure, error) {
+	x := &Exposure{}
+
+	if v, err := h.GetValue(IfdExif, EXIF_TAG_EXPOSURE_TIME); err == nil {
+		rs, ok := v.([]UnsignedRational)
+		if !ok {
+			return nil, h.entryError(IfdExif, EXIF_TAG_EXPOSURE_TIME, FormatUnsignedRational, ErrValueNotMatch)
+		}
+		if len(rs) != 0 {
+			x.ExposureTime = rs[0]
+		}
+	} else if !errors.Is(err, ErrNotFoundEntry) {
+		return nil, err
+	}
+	t := x.ExposureTime.Float64()
+	if !(t > 0) {
+		tv, ok, err := h.getFloat(IfdExif, EXIF_TAG_SHUTTER_SPEED_VALUE)
+		if err != nil {
+			return nil, err
+		}
+		t = math.Pow(2, -tv)
+		if ok && 1/t < math.MaxUint32 && t*10 < math.MaxUint32 {
+			x.ExposureTime = exposureRational(t)
+		}
+	}
+	if t := x.ExposureTime.Float64(); t > 0 {
+		x.ExposureTimeString = formatExposureTime(t)
+	}
+
+	fnumber, ok, err := h.getFloat(IfdExif, EXIF_TAG_FNUMBER)
+	if err != nil {
+		return nil, err
+	}
+	x.FNumber = fnumber
+	if !ok {
+		av, ok, err := h.getFloat(IfdExif, EXIF_TAG_APERTURE_VALUE)
+		if err != nil {
+			return nil, err
+		}
+		if n := math.Round(math.Pow(2, av/2)*10) / 10; ok && !math.IsInf(n, 0) {
+			x.FNumber = n
+		}
+	}
+
+	if err := h.getISO(x); err != nil {
+		return nil, err
+	}
+
+	x.ExposureBias, _, err = h.getFloat(IfdExif, EXIF_TAG_EXPOSURE_BIAS_VALUE)
+	if err != nil {
+		return nil, err
+	}
+	x.FocalLength, _, err = h.getFloat(IfdExif, EXIF_TAG_FOCAL_LENGTH)
+	if err != nil {
+		return nil, err
+	}
+
+	var program, metering, flash uint16
+	shorts := []struct {
+		tag Tag
+		v   *uint16
+		ok  *bool
+	}{
+		{EXIF_TAG_EXPOSURE_PROGRAM, &program, nil},
+		{EXIF_TAG_METERING_MODE, &metering, nil},
+		{EXIF_TAG_FLASH, &flash, &x.HasFlash},
+		{EXIF_TAG_FOCAL_LENGTH_IN_35MM_FILM, &x.FocalLengthIn35mm, nil},
+		{EXIF_TAG_SENSITIVITY_TYPE, &x.SensitivityType, nil},
+	}
+	for _, s := range shorts {
+		v, ok, err := h.getFloat(IfdExif, s.tag)
+		if err != nil {
+			return nil, err
+		}
+		*s.v = uint16(v)
+		if s.ok != nil {
+			*s.ok = ok
+		}
+	}
+	x.ExposureProgram = ExposureProgram(program)
+	x.MeteringMode = MeteringMode(metering)
+	x.Flash = NewFlash(flash)
+
+	return x, nil
+}
+
+// getISO sets the sensitivity of x, once its exposure time and F-number are
+// known.
+func (h *Helper) getISO(x *Exposure) error {
+	if v, err := h.GetValue(IfdExif, EXIF_TAG_ISO_SPEED_RATINGS); err == nil {
+		vs, ok := v.([]uint16)
+		if !ok {
+			return h.entryError(IfdExif, EXIF_TAG_ISO_SPEED_RATINGS, FormatUnsignedShort, ErrValueNotMatch)
+		}
+		x.ISOValues = vs
+		if len(vs) != 0 {
+			x.ISO = uint32(vs[0])
+		}
+	} else if !errors.Is(err, ErrNotFoundEntry) {
+		return err
+	}
+
+	rei, _, err := h.getFloat(IfdExif, EXIF_TAG_RECOMMENDED_EXPOSURE_INDEX)
+	if err != nil {
+		return err
+	}
+	x.RecommendedExposureIndex = uint32(rei)
+	if x.RecommendedExposureIndex != 0 && (x.ISO == 0 || x.ISO == isoSpeedOverflow) {
+		x.ISO = x.RecommendedExposureIndex
+	}
+	if x.ISO != 0 {
+		return nil
+	}
+
+	// Av + Tv = Bv + Sv, with Sv = log2(ISO / 3.125).
+	bv, ok, err := h.getFloat(IfdExif, EXIF_TAG_BRIGHTNESS_VALUE)
+	if err != nil || !ok {
+		return err
+	}
+	t := x.ExposureTime.Float64()
+	if x.FNumber <= 0 || !(t > 0) {
+		return nil
+	}
+	av := 2 * math.Log2(x.FNumber)
+	tv := -math.Log2(t)
+	iso := math.Round(3.125 * math.Pow(2, av+tv-bv))
+	if iso >= 1 && iso <= math.MaxUint32 {
+		x.ISO = uint32(iso)
+	}
+	return nil
+}
+
+// getFloat returns the first value of a numeric entry, and whether the entry
+// is recorded with a usable value.
+func (h *Helper) getFloat(ifd Ifd, tag Tag) (float64, bool, error) {
+	v, err := h.GetValue(ifd, tag)
+	if errors.Is(err, ErrNotFoundEntry) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	var f float64
+	switch vs := v.(type) {
+	case []UnsignedRational:
+		if len(vs) == 0 {
+			return 0, false, nil
+		}
+		f = vs[0].Float64()
+	case []SignedRational:
+		if len(vs) == 0 {
+			return 0, false, nil
+		}
+		f = vs[0].Float64()
+	case []uint16:
+		if len(vs) == 0 {
+			return 0, false, nil
+		}
+		f = float64(vs[0])
+	case []uint32:
+		if len(vs) == 0 {
+			return 0, false, nil
+		}
+		f = float64(vs[0])
+	default:
+		return 0, false, h.entryError(ifd, tag, 0, ErrValueNotMatch)
+	}
+
+	// A 0 denominator records an unknown value.
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false, nil
+	}
+	return f, true, nil
+}
+
+// exposureRational returns t seconds the way cameras record it: 1/n for
+// short exposures, in tenths of a second above.
+func exposureRational(t float64) UnsignedRational {
+	if t <= 0.25 {
+		return UnsignedRational{Numerator: 1, Denominator: uint32(math.Round(1 / t))}
+	}
+	n := uint32(math.Round(t * 10))
+	if n%10 == 0 {
+		return UnsignedRational{Numerator: n / 10, Denominator: 1}
+	}
+	return UnsignedRational{Numerator: n, Denominator: 10}
+}
+
+// formatExposureTime formats t seconds as "1/250" for short exposures, or in
+// seconds such as "0.8" or "2.5".
+func formatExposureTime(t float64) string {
+	if t <= 0.25 {
+		return "1/" + strconv.FormatFloat(math.Round(1/t), 'f', -1, 64)
+	}
+	return strconv.FormatFloat(math.Round(t*10)/10, 'f', -1, 64)
+}
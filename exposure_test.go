@@ -0,0 +1,109 @@
+package exif
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetExposure(t *testing.T) {
+	data := New()
+	x, err := NewHelper(data).GetExposure()
+	require.NoError(t, err)
+	assert.Equal(t, &Exposure{}, x)
+
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_EXPOSURE_TIME, UnsignedRational{10, 2500}))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_FNUMBER, UnsignedRational{28, 10}))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_ISO_SPEED_RATINGS, []uint16{200, 400}))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_EXPOSURE_BIAS_VALUE, SignedRational{-2, 3}))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_EXPOSURE_PROGRAM, uint16(2)))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_METERING_MODE, uint16(5)))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_FLASH, uint16(0)))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_FOCAL_LENGTH, UnsignedRational{50, 1}))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_FOCAL_LENGTH_IN_35MM_FILM, uint16(75)))
+
+	x, err = NewHelper(data).GetExposure()
+	require.NoError(t, err)
+	assert.Equal(t, &Exposure{
+		ExposureTime:       UnsignedRational{10, 2500},
+		ExposureTimeString: "1/250",
+		FNumber:            2.8,
+		ISO:                200,
+		ISOValues:          []uint16{200, 400},
+		ExposureBias:       -2.0 / 3,
+		ExposureProgram:    ExposureProgramNormal,
+		MeteringMode:       MeteringModePattern,
+		HasFlash:           true,
+		FocalLength:        50,
+		FocalLengthIn35mm:  75,
+	}, x)
+
+	// The sensitivity does not fit in ISOSpeedRatings.
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_ISO_SPEED_RATINGS, uint16(65535)))
+	for tag, v := range map[Tag]interface{}{
+		EXIF_TAG_SENSITIVITY_TYPE:           uint16(2),
+		EXIF_TAG_RECOMMENDED_EXPOSURE_INDEX: uint32(102400),
+	} {
+		e := data.NewEntry(IfdExif, tag)
+		require.NoError(t, e.SetValue(v))
+		data.SetEntry(e)
+	}
+	x, err = NewHelper(data).GetExposure()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(102400), x.ISO)
+	assert.Equal(t, uint16(2), x.SensitivityType)
+	assert.Equal(t, uint32(102400), x.RecommendedExposureIndex)
+
+	// libexif does not know the tags, they are kept anyway.
+	data = parseWith(t, ReadOptions{}, newTiffBytes(binary.LittleEndian).u16(1).
+		entry(EXIF_TAG_EXIF_IFD_POINTER, FormatUnsignedLong, 1, 26).u32(0).
+		u16(3).
+		short(EXIF_TAG_ISO_SPEED_RATINGS, 65535).
+		short(EXIF_TAG_SENSITIVITY_TYPE, 2).
+		entry(EXIF_TAG_RECOMMENDED_EXPOSURE_INDEX, FormatUnsignedLong, 1, 102400).u32(0).jpeg())
+	x, err = NewHelper(data).GetExposure()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(102400), x.ISO)
+	assert.Equal(t, uint16(2), x.SensitivityType)
+
+	// A SHORT exposure time, which Set does not allow.
+	e := data.NewEntry(IfdExif, EXIF_TAG_ISO_SPEED_RATINGS)
+	require.NoError(t, e.SetValue(uint16(2)))
+	e.Tag = EXIF_TAG_EXPOSURE_TIME
+	data.SetEntry(e)
+	_, err = NewHelper(data).GetExposure()
+	assert.True(t, errors.Is(err, ErrValueNotMatch))
+	var entryErr *EntryError
+	require.True(t, errors.As(err, &entryErr))
+	assert.Equal(t, EXIF_TAG_EXPOSURE_TIME, entryErr.Tag)
+}
+
+func TestGetExposureAPEX(t *testing.T) {
+	data := New()
+	// 1/250 s at f/4 and ISO 100 in the light of Bv 7.
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_SHUTTER_SPEED_VALUE, SignedRational{7966, 1000}))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_APERTURE_VALUE, UnsignedRational{4, 1}))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_BRIGHTNESS_VALUE, SignedRational{6966, 1000}))
+
+	x, err := NewHelper(data).GetExposure()
+	require.NoError(t, err)
+	assert.Equal(t, UnsignedRational{1, 250}, x.ExposureTime)
+	assert.Equal(t, "1/250", x.ExposureTimeString)
+	assert.Equal(t, 4.0, x.FNumber)
+	assert.Equal(t, uint32(100), x.ISO)
+	assert.False(t, x.HasFlash)
+
+	// Long exposures are recorded in tenths of a second.
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_SHUTTER_SPEED_VALUE, SignedRational{-4, 3}))
+	x, err = NewHelper(data).GetExposure()
+	require.NoError(t, err)
+	assert.Equal(t, UnsignedRational{25, 10}, x.ExposureTime)
+	assert.Equal(t, "2.5", x.ExposureTimeString)
+
+	assert.Equal(t, "0.8", formatExposureTime(0.769))
+	assert.Equal(t, "1/4", formatExposureTime(0.25))
+	assert.Equal(t, "2", formatExposureTime(2))
+}
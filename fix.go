@@ -0,0 +1,54 @@
+package exif
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+)
+
+// Error messages.
+var (
+	ErrUnsupportedFix = errors.New(`fixing exif data requires libexif`)
+)
+
+// Fix makes the entries follow the specification the way libexif does when
+// loading: values are converted to the format of their tag, the entries not
+// recorded in their IFD are removed and the missing mandatory entries are
+// added with their default value. It is only useful on data read with
+// ReadOptions.NoFix or changed since. Without cgo, Fix returns
+// ErrUnsupportedFix.
+func (d *Data) Fix() error {
+	fixed, err := d.fixedEntries()
+	if err != nil {
+		return err
+	}
+
+	for key, e := range d.Raw {
+		if e.Ifd >= IfdMaxCount || isLayoutTag(e.Tag) {
+			continue
+		}
+		if _, ok := fixed[key]; !ok {
+			d.Delete(e.Ifd, e.Tag)
+		}
+	}
+
+	var changed []Entry
+	for key, e := range fixed {
+		cur, ok := d.Raw[key]
+		if ok && cur.Format == e.Format && cur.Components == e.Components && bytes.Equal(cur.Raw, e.Raw) {
+			continue
+		}
+		changed = append(changed, e)
+	}
+	sort.Slice(changed, func(i, j int) bool {
+		if changed[i].Ifd != changed[j].Ifd {
+			return changed[i].Ifd < changed[j].Ifd
+		}
+		return changed[i].Tag < changed[j].Tag
+	})
+	for i := range changed {
+		d.SetEntry(&changed[i])
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package exif
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFix(t *testing.T) {
+	src := newTiffBytes(binary.LittleEndian).u16(1).
+		short(EXIF_TAG_ORIENTATION, 1).u32(0).jpeg()
+	data := parseWith(t, ReadOptions{NoFix: true}, src)
+	require.Len(t, data.Raw, 1)
+
+	err := data.Fix()
+	if !libexifFixes {
+		assert.Equal(t, ErrUnsupportedFix, err)
+		assert.Len(t, data.Raw, 1)
+		return
+	}
+	require.NoError(t, err)
+
+	// The mandatory entries are added, along with the pointers to their IFD.
+	for _, key := range []IfdTag{
+		NewIfdTag(uint16(Ifd0), uint16(EXIF_TAG_X_RESOLUTION)),
+		NewIfdTag(uint16(Ifd0), uint16(EXIF_TAG_EXIF_IFD_POINTER)),
+		NewIfdTag(uint16(IfdExif), uint16(EXIF_TAG_EXIF_VERSION)),
+	} {
+		_, ok := data.Raw[key]
+		assert.True(t, ok, key)
+	}
+	assert.Len(t, data.Entries, len(data.Raw))
+	assert.Equal(t, EXIF_TAG_ORIENTATION, data.Entries[0].Tag)
+
+	// The data read with the default options is already fixed.
+	fixed := parseWith(t, ReadOptions{}, src)
+	for key, e := range fixed.Raw {
+		assert.Equal(t, e.Raw, data.Raw[key].Raw, key)
+	}
+	entries := len(data.Entries)
+	require.NoError(t, data.Fix())
+	assert.Len(t, data.Entries, entries)
+}
@@ -0,0 +1,199 @@
+package exif
+
+// tagFormats lists the formats the TIFF 6.0 and EXIF 2.32 specifications
+// allow for each tag outside of the GPS IFD.
+var tagFormats = map[Tag][]EntryFormat{
+	EXIF_TAG_INTEROPERABILITY_INDEX:                   {FormatAscii},
+	EXIF_TAG_INTEROPERABILITY_VERSION:                 {FormatUndefined},
+	EXIF_TAG_NEW_SUBFILE_TYPE:                         {FormatUnsignedLong},
+	EXIF_TAG_IMAGE_WIDTH:                              {FormatUnsignedShort, FormatUnsignedLong},
+	EXIF_TAG_IMAGE_LENGTH:                             {FormatUnsignedShort, FormatUnsignedLong},
+	EXIF_TAG_BITS_PER_SAMPLE:                          {FormatUnsignedShort},
+	EXIF_TAG_COMPRESSION:                              {FormatUnsignedShort},
+	EXIF_TAG_PHOTOMETRIC_INTERPRETATION:               {FormatUnsignedShort},
+	EXIF_TAG_FILL_ORDER:                               {FormatUnsignedShort},
+	EXIF_TAG_DOCUMENT_NAME:                            {FormatAscii},
+	EXIF_TAG_IMAGE_DESCRIPTION:                        {FormatAscii},
+	EXIF_TAG_MAKE:                                     {FormatAscii},
+	EXIF_TAG_MODEL:                                    {FormatAscii},
+	EXIF_TAG_STRIP_OFFSETS:                            {FormatUnsignedShort, FormatUnsignedLong},
+	EXIF_TAG_ORIENTATION:                              {FormatUnsignedShort},
+	EXIF_TAG_SAMPLES_PER_PIXEL:                        {FormatUnsignedShort},
+	EXIF_TAG_ROWS_PER_STRIP:                           {FormatUnsignedShort, FormatUnsignedLong},
+	EXIF_TAG_STRIP_BYTE_COUNTS:                        {FormatUnsignedShort, FormatUnsignedLong},
+	EXIF_TAG_X_RESOLUTION:                             {FormatUnsignedRational},
+	EXIF_TAG_Y_RESOLUTION:                             {FormatUnsignedRational},
+	EXIF_TAG_PLANAR_CONFIGURATION:                     {FormatUnsignedShort},
+	EXIF_TAG_RESOLUTION_UNIT:                          {FormatUnsignedShort},
+	EXIF_TAG_TRANSFER_FUNCTION:                        {FormatUnsignedShort},
+	EXIF_TAG_SOFTWARE:                                 {FormatAscii},
+	EXIF_TAG_DATE_TIME:                                {FormatAscii},
+	EXIF_TAG_ARTIST:                                   {FormatAscii},
+	EXIF_TAG_WHITE_POINT:                              {FormatUnsignedRational},
+	EXIF_TAG_PRIMARY_CHROMATICITIES:                   {FormatUnsignedRational},
+	EXIF_TAG_SUB_IFDS:                                 {FormatUnsignedLong},
+	EXIF_TAG_TRANSFER_RANGE:                           {FormatUnsignedShort},
+	EXIF_TAG_JPEG_PROC:                                {FormatUnsignedShort},
+	EXIF_TAG_JPEG_INTERCHANGE_FORMAT:                  {FormatUnsignedLong},
+	EXIF_TAG_JPEG_INTERCHANGE_FORMAT_LENGTH:           {FormatUnsignedLong},
+	EXIF_TAG_YCBCR_COEFFICIENTS:                       {FormatUnsignedRational},
+	EXIF_TAG_YCBCR_SUB_SAMPLING:                       {FormatUnsignedShort},
+	EXIF_TAG_YCBCR_POSITIONING:                        {FormatUnsignedShort},
+	EXIF_TAG_REFERENCE_BLACK_WHITE:                    {FormatUnsignedRational},
+	EXIF_TAG_XML_PACKET:                               {FormatUnsignedByte, FormatUndefined},
+	EXIF_TAG_RELATED_IMAGE_FILE_FORMAT:                {FormatAscii},
+	EXIF_TAG_RELATED_IMAGE_WIDTH:                      {FormatUnsignedShort, FormatUnsignedLong},
+	EXIF_TAG_RELATED_IMAGE_LENGTH:                     {FormatUnsignedShort, FormatUnsignedLong},
+	EXIF_TAG_CFA_REPEAT_PATTERN_DIM:                   {FormatUnsignedShort},
+	EXIF_TAG_CFA_PATTERN:                              {FormatUnsignedByte},
+	EXIF_TAG_BATTERY_LEVEL:                            {FormatUnsignedRational, FormatAscii},
+	EXIF_TAG_COPYRIGHT:                                {FormatAscii},
+	EXIF_TAG_EXPOSURE_TIME:                            {FormatUnsignedRational},
+	EXIF_TAG_FNUMBER:                                  {FormatUnsignedRational},
+	EXIF_TAG_IPTC_NAA:                                 {FormatUnsignedLong, FormatUnsignedByte, FormatUndefined},
+	EXIF_TAG_IMAGE_RESOURCES:                          {FormatUnsignedByte, FormatUndefined},
+	EXIF_TAG_EXIF_IFD_POINTER:                         {FormatUnsignedLong},
+	EXIF_TAG_INTER_COLOR_PROFILE:                      {FormatUndefined},
+	EXIF_TAG_EXPOSURE_PROGRAM:                         {FormatUnsignedShort},
+	EXIF_TAG_SPECTRAL_SENSITIVITY:                     {FormatAscii},
+	EXIF_TAG_GPS_INFO_IFD_POINTER:                     {FormatUnsignedLong},
+	EXIF_TAG_ISO_SPEED_RATINGS:                        {FormatUnsignedShort},
+	EXIF_TAG_OECF:                                     {FormatUndefined},
+	EXIF_TAG_TIME_ZONE_OFFSET:                         {FormatSignedShort},
+	EXIF_TAG_SENSITIVITY_TYPE:                         {FormatUnsignedShort},
+	EXIF_TAG_STANDARD_OUTPUT_SENSITIVITY:              {FormatUnsignedLong},
+	EXIF_TAG_RECOMMENDED_EXPOSURE_INDEX:               {FormatUnsignedLong},
+	EXIF_TAG_EXIF_VERSION:                             {FormatUndefined},
+	EXIF_TAG_DATE_TIME_ORIGINAL:                       {FormatAscii},
+	EXIF_TAG_DATE_TIME_DIGITIZED:                      {FormatAscii},
+	EXIF_TAG_OFFSET_TIME:                              {FormatAscii},
+	EXIF_TAG_OFFSET_TIME_ORIGINAL:                     {FormatAscii},
+	EXIF_TAG_OFFSET_TIME_DIGITIZED:                    {FormatAscii},
+	EXIF_TAG_COMPONENTS_CONFIGURATION:                 {FormatUndefined},
+	EXIF_TAG_COMPRESSED_BITS_PER_PIXEL:                {FormatUnsignedRational},
+	EXIF_TAG_SHUTTER_SPEED_VALUE:                      {FormatSignedRational},
+	EXIF_TAG_APERTURE_VALUE:                           {FormatUnsignedRational},
+	EXIF_TAG_BRIGHTNESS_VALUE:                         {FormatSignedRational},
+	EXIF_TAG_EXPOSURE_BIAS_VALUE:                      {FormatSignedRational},
+	EXIF_TAG_MAX_APERTURE_VALUE:                       {FormatUnsignedRational},
+	EXIF_TAG_SUBJECT_DISTANCE:                         {FormatUnsignedRational},
+	EXIF_TAG_METERING_MODE:                            {FormatUnsignedShort},
+	EXIF_TAG_LIGHT_SOURCE:                             {FormatUnsignedShort},
+	EXIF_TAG_FLASH:                                    {FormatUnsignedShort},
+	EXIF_TAG_FOCAL_LENGTH:                             {FormatUnsignedRational},
+	EXIF_TAG_SUBJECT_AREA:                             {FormatUnsignedShort},
+	EXIF_TAG_TIFF_EP_STANDARD_ID:                      {FormatUnsignedByte},
+	EXIF_TAG_MAKER_NOTE:                               {FormatUndefined},
+	EXIF_TAG_USER_COMMENT:                             {FormatUndefined},
+	EXIF_TAG_SUB_SEC_TIME:                             {FormatAscii},
+	EXIF_TAG_SUB_SEC_TIME_ORIGINAL:                    {FormatAscii},
+	EXIF_TAG_SUB_SEC_TIME_DIGITIZED:                   {FormatAscii},
+	EXIF_TAG_XP_TITLE:                                 {FormatUnsignedByte},
+	EXIF_TAG_XP_COMMENT:                               {FormatUnsignedByte},
+	EXIF_TAG_XP_AUTHOR:                                {FormatUnsignedByte},
+	EXIF_TAG_XP_KEYWORDS:                              {FormatUnsignedByte},
+	EXIF_TAG_XP_SUBJECT:                               {FormatUnsignedByte},
+	EXIF_TAG_FLASH_PIX_VERSION:                        {FormatUndefined},
+	EXIF_TAG_COLOR_SPACE:                              {FormatUnsignedShort},
+	EXIF_TAG_PIXEL_X_DIMENSION:                        {FormatUnsignedShort, FormatUnsignedLong},
+	EXIF_TAG_PIXEL_Y_DIMENSION:                        {FormatUnsignedShort, FormatUnsignedLong},
+	EXIF_TAG_RELATED_SOUND_FILE:                       {FormatAscii},
+	EXIF_TAG_INTEROPERABILITY_IFD_POINTER:             {FormatUnsignedLong},
+	EXIF_TAG_FLASH_ENERGY:                             {FormatUnsignedRational},
+	EXIF_TAG_SPATIAL_FREQUENCY_RESPONSE:               {FormatUndefined},
+	EXIF_TAG_FOCAL_PLANE_X_RESOLUTION:                 {FormatUnsignedRational},
+	EXIF_TAG_FOCAL_PLANE_Y_RESOLUTION:                 {FormatUnsignedRational},
+	EXIF_TAG_FOCAL_PLANE_RESOLUTION_UNIT:              {FormatUnsignedShort},
+	EXIF_TAG_SUBJECT_LOCATION:                         {FormatUnsignedShort},
+	EXIF_TAG_EXPOSURE_INDEX:                           {FormatUnsignedRational},
+	EXIF_TAG_SENSING_METHOD:                           {FormatUnsignedShort},
+	EXIF_TAG_FILE_SOURCE:                              {FormatUndefined},
+	EXIF_TAG_SCENE_TYPE:                               {FormatUndefined},
+	EXIF_TAG_NEW_CFA_PATTERN:                          {FormatUndefined},
+	EXIF_TAG_CUSTOM_RENDERED:                          {FormatUnsignedShort},
+	EXIF_TAG_EXPOSURE_MODE:                            {FormatUnsignedShort},
+	EXIF_TAG_WHITE_BALANCE:                            {FormatUnsignedShort},
+	EXIF_TAG_DIGITAL_ZOOM_RATIO:                       {FormatUnsignedRational},
+	EXIF_TAG_FOCAL_LENGTH_IN_35MM_FILM:                {FormatUnsignedShort},
+	EXIF_TAG_SCENE_CAPTURE_TYPE:                       {FormatUnsignedShort},
+	EXIF_TAG_GAIN_CONTROL:                             {FormatUnsignedShort},
+	EXIF_TAG_CONTRAST:                                 {FormatUnsignedShort},
+	EXIF_TAG_SATURATION:                               {FormatUnsignedShort},
+	EXIF_TAG_SHARPNESS:                                {FormatUnsignedShort},
+	EXIF_TAG_DEVICE_SETTING_DESCRIPTION:               {FormatUndefined},
+	EXIF_TAG_SUBJECT_DISTANCE_RANGE:                   {FormatUnsignedShort},
+	EXIF_TAG_IMAGE_UNIQUE_ID:                          {FormatAscii},
+	EXIF_TAG_CAMERA_OWNER_NAME:                        {FormatAscii},
+	EXIF_TAG_BODY_SERIAL_NUMBER:                       {FormatAscii},
+	EXIF_TAG_LENS_SPECIFICATION:                       {FormatUnsignedRational},
+	EXIF_TAG_LENS_MAKE:                                {FormatAscii},
+	EXIF_TAG_LENS_MODEL:                               {FormatAscii},
+	EXIF_TAG_LENS_SERIAL_NUMBER:                       {FormatAscii},
+	EXIF_TAG_COMPOSITE_IMAGE:                          {FormatUnsignedShort},
+	EXIF_TAG_SOURCE_IMAGE_NUMBER_OF_COMPOSITE_IMAGE:   {FormatUnsignedShort},
+	EXIF_TAG_SOURCE_EXPOSURE_TIMES_OF_COMPOSITE_IMAGE: {FormatUndefined},
+	EXIF_TAG_GAMMA:                                    {FormatUnsignedRational},
+	EXIF_TAG_PRINT_IMAGE_MATCHING:                     {FormatUndefined},
+	EXIF_TAG_PADDING:                                  {FormatUndefined},
+}
+
+// gpsTagFormats lists the formats the EXIF 2.32 specification allows for
+// each tag of the GPS IFD.
+var gpsTagFormats = map[Tag][]EntryFormat{
+	EXIF_TAG_GPS_VERSION_ID:          {FormatUnsignedByte},
+	EXIF_TAG_GPS_LATITUDE_REF:        {FormatAscii},
+	EXIF_TAG_GPS_LATITUDE:            {FormatUnsignedRational},
+	EXIF_TAG_GPS_LONGITUDE_REF:       {FormatAscii},
+	EXIF_TAG_GPS_LONGITUDE:           {FormatUnsignedRational},
+	EXIF_TAG_GPS_ALTITUDE_REF:        {FormatUnsignedByte},
+	EXIF_TAG_GPS_ALTITUDE:            {FormatUnsignedRational},
+	EXIF_TAG_GPS_TIME_STAMP:          {FormatUnsignedRational},
+	EXIF_TAG_GPS_SATELLITES:          {FormatAscii},
+	EXIF_TAG_GPS_STATUS:              {FormatAscii},
+	EXIF_TAG_GPS_MEASURE_MODE:        {FormatAscii},
+	EXIF_TAG_GPS_DOP:                 {FormatUnsignedRational},
+	EXIF_TAG_GPS_SPEED_REF:           {FormatAscii},
+	EXIF_TAG_GPS_SPEED:               {FormatUnsignedRational},
+	EXIF_TAG_GPS_TRACK_REF:           {FormatAscii},
+	EXIF_TAG_GPS_TRACK:               {FormatUnsignedRational},
+	EXIF_TAG_GPS_IMG_DIRECTION_REF:   {FormatAscii},
+	EXIF_TAG_GPS_IMG_DIRECTION:       {FormatUnsignedRational},
+	EXIF_TAG_GPS_MAP_DATUM:           {FormatAscii},
+	EXIF_TAG_GPS_DEST_LATITUDE_REF:   {FormatAscii},
+	EXIF_TAG_GPS_DEST_LATITUDE:       {FormatUnsignedRational},
+	EXIF_TAG_GPS_DEST_LONGITUDE_REF:  {FormatAscii},
+	EXIF_TAG_GPS_DEST_LONGITUDE:      {FormatUnsignedRational},
+	EXIF_TAG_GPS_DEST_BEARING_REF:    {FormatAscii},
+	EXIF_TAG_GPS_DEST_BEARING:        {FormatUnsignedRational},
+	EXIF_TAG_GPS_DEST_DISTANCE_REF:   {FormatAscii},
+	EXIF_TAG_GPS_DEST_DISTANCE:       {FormatUnsignedRational},
+	EXIF_TAG_GPS_PROCESSING_METHOD:   {FormatUndefined},
+	EXIF_TAG_GPS_AREA_INFORMATION:    {FormatUndefined},
+	EXIF_TAG_GPS_DATE_STAMP:          {FormatAscii},
+	EXIF_TAG_GPS_DIFFERENTIAL:        {FormatUnsignedShort},
+	EXIF_TAG_GPS_H_POSITIONING_ERROR: {FormatUnsignedRational},
+}
+
+// ExpectedFormats returns the formats allowed for tag in ifd, or nil when
+// the tag is not known.
+func ExpectedFormats(ifd Ifd, tag Tag) []EntryFormat {
+	if ifd == IfdGps {
+		return gpsTagFormats[tag]
+	}
+	return tagFormats[tag]
+}
+
+// checkFormat reports whether format may be used for tag in ifd. Unknown tags
+// accept any format.
+func checkFormat(ifd Ifd, tag Tag, format EntryFormat) error {
+	formats := ExpectedFormats(ifd, tag)
+	if formats == nil {
+		return nil
+	}
+	for _, f := range formats {
+		if f == format {
+			return nil
+		}
+	}
+	return &EntryError{Ifd: ifd, Tag: tag, Want: formats[0], Got: format, Err: ErrFormatNotMatch}
+}
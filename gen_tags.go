@@ -0,0 +1,68 @@
+//go:build ignore
+
+// gen_tags writes tag_table.go, the copy of the libexif tag table used when
+// building without cgo.
+//
+//	go run gen_tags.go
+package main
+
+/*
+#cgo pkg-config: libexif
+#include <libexif/exif-tag.h>
+*/
+import "C"
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+)
+
+// ifdCount is EXIF_IFD_COUNT.
+const ifdCount = 5
+
+func main() {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "// Code generated by gen_tags.go from the libexif tag table. DO NOT EDIT.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "//go:build !cgo")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "package exif")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "var tagTable = []tagTableEntry{")
+
+	count := int(C.exif_tag_table_count())
+	for i := 0; i < count; i++ {
+		cname := C.exif_tag_table_get_name(C.uint(i))
+		if cname == nil {
+			continue
+		}
+		name := C.GoString(cname)
+		tag := C.exif_tag_table_get_tag(C.uint(i))
+
+		var ifds uint8
+		var title, description string
+		for ifd := 0; ifd < ifdCount; ifd++ {
+			n := C.exif_tag_get_name_in_ifd(tag, C.ExifIfd(ifd))
+			if n == nil || C.GoString(n) != name {
+				continue
+			}
+			ifds |= 1 << ifd
+			title = C.GoString(C.exif_tag_get_title_in_ifd(tag, C.ExifIfd(ifd)))
+			description = C.GoString(C.exif_tag_get_description_in_ifd(tag, C.ExifIfd(ifd)))
+		}
+
+		fmt.Fprintf(&buf, "\t{0x%04x, %q, %q, %q, 0x%02x},\n", int(tag), name, title, description, ifds)
+	}
+	fmt.Fprintln(&buf, "}")
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("tag_table.go", out, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
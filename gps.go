@@ -0,0 +1,512 @@
+package exif
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Error messages.
+var (
+	ErrValueOutOfRange = errors.New(`value out of range`)
+)
+
+// GPSDateLayout is the layout of EXIF_TAG_GPS_DATE_STAMP.
+const GPSDateLayout = "2006:01:02"
+
+// GetGPSTime returns the UTC time recorded by the GPS receiver, built from
+// GPSDateStamp and GPSTimeStamp.
+func (h *Helper) GetGPSTime() (time.Time, error) {
+	s, err := h.GetString(IfdGps, EXIF_TAG_GPS_DATE_STAMP)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// Some writers use dashes, as in ISO 8601.
+	if len(s) == 10 && s[4] == '-' && s[7] == '-' {
+		s = s[:4] + ":" + s[5:7] + ":" + s[8:]
+	}
+	date, err := time.ParseInLocation(GPSDateLayout, s, time.UTC)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	v, err := h.GetValue(IfdGps, EXIF_TAG_GPS_TIME_STAMP)
+	if err != nil {
+		return time.Time{}, err
+	}
+	rs, ok := v.([]UnsignedRational)
+	if !ok {
+		return time.Time{}, h.entryError(IfdGps, EXIF_TAG_GPS_TIME_STAMP, FormatUnsignedRational, ErrValueNotMatch)
+	}
+	if len(rs) != 3 {
+		return time.Time{}, h.entryError(IfdGps, EXIF_TAG_GPS_TIME_STAMP, FormatUnsignedRational, ErrLengthNotMatch)
+	}
+
+	var seconds float64
+	for i, unit := range []float64{3600, 60, 1} {
+		if rs[i].Denominator == 0 {
+			return time.Time{}, h.entryError(IfdGps, EXIF_TAG_GPS_TIME_STAMP, FormatUnsignedRational, ErrValueNotMatch)
+		}
+		seconds += unit * float64(rs[i].Numerator) / float64(rs[i].Denominator)
+	}
+
+	return date.Add(time.Duration(math.Round(seconds * float64(time.Second)))), nil
+}
+
+// InferTimezoneOffset estimates the UTC offset the camera clock was set to,
+// by comparing the local capture time with the GPS time. The result is
+// rounded to the nearest quarter of an hour.
+func (h *Helper) InferTimezoneOffset() (time.Duration, error) {
+	gps, err := h.GetGPSTime()
+	if err != nil {
+		return 0, err
+	}
+
+	dt, err := h.GetDateTime()
+	if err != nil {
+		return 0, err
+	}
+	t := dt.Time
+	local := time.Date(t.Year(), t.Month(), t.Day(),
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+
+	offset := local.Sub(gps).Round(15 * time.Minute)
+	if offset < -12*time.Hour || offset > 14*time.Hour {
+		return 0, ErrInvalidOffset
+	}
+	return offset, nil
+}
+
+// GPSInfo holds the tags of the GPS IFD. Fields are nil when the matching tag
+// is missing or cannot be decoded. Coordinates are given in decimal degrees,
+// negative for the south and west, and the altitude is negative below sea
+// level.
+type GPSInfo struct {
+	VersionID         []byte
+	Latitude          *float64
+	Longitude         *float64
+	Altitude          *float64
+	DateStamp         *string
+	TimeStamp         *time.Duration // since midnight UTC
+	Time              *time.Time     // DateStamp and TimeStamp combined
+	Satellites        *string
+	Status            *string // "A" measurement in progress, "V" interrupted
+	MeasureMode       *string // "2" or "3" dimensional
+	DOP               *float64
+	SpeedRef          *string // "K" km/h, "M" mph, "N" knots
+	Speed             *float64
+	TrackRef          *string // "T" true, "M" magnetic direction
+	Track             *float64
+	ImgDirectionRef   *string
+	ImgDirection      *float64
+	MapDatum          *string
+	DestLatitude      *float64
+	DestLongitude     *float64
+	DestBearingRef    *string
+	DestBearing       *float64
+	DestDistanceRef   *string // "K" km, "M" miles, "N" nautical miles
+	DestDistance      *float64
+	ProcessingMethod  *string
+	AreaInformation   *string
+	Differential      *uint16 // 1 when differential correction was applied
+	HPositioningError *float64
+}
+
+// GetGPSInfo reads every tag of the GPS IFD. It only fails when the file has
+// no GPS data at all.
+func (h *Helper) GetGPSInfo() (*GPSInfo, error) {
+	empty := true
+	for _, e := range h.Raw {
+		if e.Ifd == IfdGps {
+			empty = false
+			break
+		}
+	}
+	if empty {
+		return nil, &EntryError{Ifd: Ifd0, Tag: EXIF_TAG_GPS_INFO_IFD_POINTER, Err: ErrNotFoundEntry}
+	}
+
+	info := &GPSInfo{
+		Latitude:          h.optCoordinate(EXIF_TAG_GPS_LATITUDE, EXIF_TAG_GPS_LATITUDE_REF, 'S'),
+		Longitude:         h.optCoordinate(EXIF_TAG_GPS_LONGITUDE, EXIF_TAG_GPS_LONGITUDE_REF, 'W'),
+		DateStamp:         h.optString(EXIF_TAG_GPS_DATE_STAMP),
+		Satellites:        h.optString(EXIF_TAG_GPS_SATELLITES),
+		Status:            h.optString(EXIF_TAG_GPS_STATUS),
+		MeasureMode:       h.optString(EXIF_TAG_GPS_MEASURE_MODE),
+		DOP:               h.optRational(EXIF_TAG_GPS_DOP),
+		SpeedRef:          h.optString(EXIF_TAG_GPS_SPEED_REF),
+		Speed:             h.optRational(EXIF_TAG_GPS_SPEED),
+		TrackRef:          h.optString(EXIF_TAG_GPS_TRACK_REF),
+		Track:             h.optRational(EXIF_TAG_GPS_TRACK),
+		ImgDirectionRef:   h.optString(EXIF_TAG_GPS_IMG_DIRECTION_REF),
+		ImgDirection:      h.optRational(EXIF_TAG_GPS_IMG_DIRECTION),
+		MapDatum:          h.optString(EXIF_TAG_GPS_MAP_DATUM),
+		DestLatitude:      h.optCoordinate(EXIF_TAG_GPS_DEST_LATITUDE, EXIF_TAG_GPS_DEST_LATITUDE_REF, 'S'),
+		DestLongitude:     h.optCoordinate(EXIF_TAG_GPS_DEST_LONGITUDE, EXIF_TAG_GPS_DEST_LONGITUDE_REF, 'W'),
+		DestBearingRef:    h.optString(EXIF_TAG_GPS_DEST_BEARING_REF),
+		DestBearing:       h.optRational(EXIF_TAG_GPS_DEST_BEARING),
+		DestDistanceRef:   h.optString(EXIF_TAG_GPS_DEST_DISTANCE_REF),
+		DestDistance:      h.optRational(EXIF_TAG_GPS_DEST_DISTANCE),
+		ProcessingMethod:  h.optEncodedString(EXIF_TAG_GPS_PROCESSING_METHOD),
+		AreaInformation:   h.optEncodedString(EXIF_TAG_GPS_AREA_INFORMATION),
+		HPositioningError: h.optRational(EXIF_TAG_GPS_H_POSITIONING_ERROR),
+	}
+
+	if v, err := h.GetValue(IfdGps, EXIF_TAG_GPS_VERSION_ID); err == nil {
+		info.VersionID, _ = v.([]byte)
+	}
+
+	if alt := h.optRational(EXIF_TAG_GPS_ALTITUDE); alt != nil {
+		if v, err := h.GetValue(IfdGps, EXIF_TAG_GPS_ALTITUDE_REF); err == nil {
+			if ref, ok := v.([]byte); ok && len(ref) != 0 && ref[0] == 1 {
+				*alt = -*alt
+			}
+		}
+		info.Altitude = alt
+	}
+
+	if v, err := h.GetValue(IfdGps, EXIF_TAG_GPS_TIME_STAMP); err == nil {
+		if rs, ok := v.([]UnsignedRational); ok && len(rs) == 3 {
+			seconds := rs[0].Float64()*3600 + rs[1].Float64()*60 + rs[2].Float64()
+			if !math.IsNaN(seconds) {
+				d := time.Duration(math.Round(seconds * float64(time.Second)))
+				info.TimeStamp = &d
+			}
+		}
+	}
+	if t, err := h.GetGPSTime(); err == nil {
+		info.Time = &t
+	}
+
+	if v, err := h.GetValue(IfdGps, EXIF_TAG_GPS_DIFFERENTIAL); err == nil {
+		if us, ok := v.([]uint16); ok && len(us) != 0 {
+			info.Differential = &us[0]
+		}
+	}
+
+	return info, nil
+}
+
+func (h *Helper) optString(tag Tag) *string {
+	s, err := h.GetString(IfdGps, tag)
+	if err != nil {
+		return nil
+	}
+	return &s
+}
+
+func (h *Helper) optEncodedString(tag Tag) *string {
+	e := h.GetEntry(uint16(IfdGps), uint16(tag))
+	if e == nil {
+		return nil
+	}
+	s, err := e.ReadAsEncodedString()
+	if err != nil {
+		return nil
+	}
+	return &s
+}
+
+func (h *Helper) optRational(tag Tag) *float64 {
+	v, err := h.GetValue(IfdGps, tag)
+	if err != nil {
+		return nil
+	}
+	rs, ok := v.([]UnsignedRational)
+	if !ok || len(rs) == 0 {
+		return nil
+	}
+	f := rs[0].Float64()
+	if math.IsNaN(f) {
+		return nil
+	}
+	return &f
+}
+
+// optCoordinate is like getCoordinate, but assumes north or east when the
+// reference tag is missing.
+func (h *Helper) optCoordinate(tag, refTag Tag, negRef byte) *float64 {
+	f, err := h.getDegrees(tag)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+	if ref, err := h.GetString(IfdGps, refTag); err == nil && len(ref) != 0 {
+		if strings.ToUpper(ref[:1])[0] == negRef {
+			f = -f
+		}
+	}
+	return &f
+}
+
+// DefaultGPSPrecision is the denominator used for the rationals written by
+// SetLocation and SetGPSInfo when Data.GPSPrecision is 0. For the seconds of
+// a coordinate, it amounts to a few millimeters.
+const DefaultGPSPrecision = 10000
+
+// gpsVersion is the GPSVersionID of the EXIF 2.3 specification.
+var gpsVersion = []byte{2, 3, 0, 0}
+
+// SetLocation geotags the image.
+func (d *Data) SetLocation(loc Location) error {
+	return d.SetGPSInfo(&GPSInfo{
+		Latitude:  &loc.Latitude,
+		Longitude: &loc.Longitude,
+		Altitude:  &loc.Altitude,
+	})
+}
+
+// SetGPSInfo writes the non nil fields of info to the GPS IFD. It is the
+// counterpart of Helper.GetGPSInfo. When both are given, Time takes
+// precedence over DateStamp and TimeStamp. Nothing is written when a value
+// cannot be recorded, such as a latitude beyond 90 degrees, a negative speed
+// or a value too large for the precision.
+func (d *Data) SetGPSInfo(info *GPSInfo) error {
+	precision := d.GPSPrecision
+	if precision == 0 {
+		precision = DefaultGPSPrecision
+	}
+
+	// The entries are all built before storing any of them.
+	var entries []*Entry
+	set := func(tag Tag, value interface{}) error {
+		e, err := d.newValueEntry(IfdGps, tag, value)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, e)
+		return nil
+	}
+	rangeError := func(tag Tag, err error) error {
+		return &EntryError{Ifd: IfdGps, Tag: tag, Err: err}
+	}
+
+	version := info.VersionID
+	if version == nil {
+		version = gpsVersion
+	}
+	if err := set(EXIF_TAG_GPS_VERSION_ID, version); err != nil {
+		return err
+	}
+
+	coordinates := []struct {
+		value          *float64
+		max            float64
+		tag, refTag    Tag
+		posRef, negRef string
+	}{
+		{info.Latitude, 90, EXIF_TAG_GPS_LATITUDE, EXIF_TAG_GPS_LATITUDE_REF, "N", "S"},
+		{info.Longitude, 180, EXIF_TAG_GPS_LONGITUDE, EXIF_TAG_GPS_LONGITUDE_REF, "E", "W"},
+		{info.DestLatitude, 90, EXIF_TAG_GPS_DEST_LATITUDE, EXIF_TAG_GPS_DEST_LATITUDE_REF, "N", "S"},
+		{info.DestLongitude, 180, EXIF_TAG_GPS_DEST_LONGITUDE, EXIF_TAG_GPS_DEST_LONGITUDE_REF, "E", "W"},
+	}
+	for _, c := range coordinates {
+		if c.value == nil {
+			continue
+		}
+		if math.Abs(*c.value) > c.max {
+			return rangeError(c.tag, fmt.Errorf("%w: %g degrees", ErrValueOutOfRange, *c.value))
+		}
+		dms, err := DegreesToDMS(*c.value, precision)
+		if err != nil {
+			return rangeError(c.tag, err)
+		}
+		ref := c.posRef
+		if *c.value < 0 {
+			ref = c.negRef
+		}
+		if err := set(c.tag, dms); err != nil {
+			return err
+		}
+		if err := set(c.refTag, ref); err != nil {
+			return err
+		}
+	}
+
+	if info.Altitude != nil {
+		var ref byte
+		if *info.Altitude < 0 {
+			ref = 1
+		}
+		alt, err := toRational(math.Abs(*info.Altitude), precision)
+		if err != nil {
+			return rangeError(EXIF_TAG_GPS_ALTITUDE, err)
+		}
+		if err := set(EXIF_TAG_GPS_ALTITUDE, alt); err != nil {
+			return err
+		}
+		if err := set(EXIF_TAG_GPS_ALTITUDE_REF, []byte{ref}); err != nil {
+			return err
+		}
+	}
+
+	dateStamp, timeStamp := info.DateStamp, info.TimeStamp
+	if info.Time != nil {
+		t := info.Time.UTC()
+		date := t.Format(GPSDateLayout)
+		clock := t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+		dateStamp, timeStamp = &date, &clock
+	}
+	if dateStamp != nil {
+		if err := set(EXIF_TAG_GPS_DATE_STAMP, *dateStamp); err != nil {
+			return err
+		}
+	}
+	if timeStamp != nil {
+		hms, err := durationToHMS(*timeStamp, precision)
+		if err != nil {
+			return rangeError(EXIF_TAG_GPS_TIME_STAMP, err)
+		}
+		if err := set(EXIF_TAG_GPS_TIME_STAMP, hms); err != nil {
+			return err
+		}
+	}
+
+	strs := []struct {
+		value *string
+		tag   Tag
+	}{
+		{info.Satellites, EXIF_TAG_GPS_SATELLITES},
+		{info.Status, EXIF_TAG_GPS_STATUS},
+		{info.MeasureMode, EXIF_TAG_GPS_MEASURE_MODE},
+		{info.SpeedRef, EXIF_TAG_GPS_SPEED_REF},
+		{info.TrackRef, EXIF_TAG_GPS_TRACK_REF},
+		{info.ImgDirectionRef, EXIF_TAG_GPS_IMG_DIRECTION_REF},
+		{info.MapDatum, EXIF_TAG_GPS_MAP_DATUM},
+		{info.DestBearingRef, EXIF_TAG_GPS_DEST_BEARING_REF},
+		{info.DestDistanceRef, EXIF_TAG_GPS_DEST_DISTANCE_REF},
+	}
+	for _, s := range strs {
+		if s.value == nil {
+			continue
+		}
+		if err := set(s.tag, *s.value); err != nil {
+			return err
+		}
+	}
+
+	rationals := []struct {
+		value *float64
+		tag   Tag
+	}{
+		{info.DOP, EXIF_TAG_GPS_DOP},
+		{info.Speed, EXIF_TAG_GPS_SPEED},
+		{info.Track, EXIF_TAG_GPS_TRACK},
+		{info.ImgDirection, EXIF_TAG_GPS_IMG_DIRECTION},
+		{info.DestBearing, EXIF_TAG_GPS_DEST_BEARING},
+		{info.DestDistance, EXIF_TAG_GPS_DEST_DISTANCE},
+		{info.HPositioningError, EXIF_TAG_GPS_H_POSITIONING_ERROR},
+	}
+	for _, r := range rationals {
+		if r.value == nil {
+			continue
+		}
+		v, err := toRational(*r.value, precision)
+		if err != nil {
+			return rangeError(r.tag, err)
+		}
+		if err := set(r.tag, v); err != nil {
+			return err
+		}
+	}
+
+	encoded := []struct {
+		value *string
+		tag   Tag
+	}{
+		{info.ProcessingMethod, EXIF_TAG_GPS_PROCESSING_METHOD},
+		{info.AreaInformation, EXIF_TAG_GPS_AREA_INFORMATION},
+	}
+	for _, s := range encoded {
+		if s.value == nil {
+			continue
+		}
+		raw := append([]byte("ASCII\x00\x00\x00"), *s.value...)
+		if err := set(s.tag, raw); err != nil {
+			return err
+		}
+	}
+
+	if info.Differential != nil {
+		if err := set(EXIF_TAG_GPS_DIFFERENTIAL, *info.Differential); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range entries {
+		d.SetEntry(e)
+	}
+	return nil
+}
+
+// DegreesToDMS converts decimal degrees to the degrees, minutes and seconds
+// rationals of the GPS coordinate tags. The sign is dropped, and the seconds
+// use precision as their denominator. It fails with ErrValueOutOfRange when
+// degrees is not a number, or when the seconds do not fit with precision.
+func DegreesToDMS(degrees float64, precision uint32) ([]UnsignedRational, error) {
+	degrees = math.Abs(degrees)
+	total := degrees * 3600 * float64(precision)
+	if precision == 0 || math.IsNaN(total) || total >= 1<<63 {
+		return nil, fmt.Errorf("%w: %g degrees with precision %d", ErrValueOutOfRange, degrees, precision)
+	}
+
+	perMinute := 60 * uint64(precision)
+	perDegree := 60 * perMinute
+	sec := uint64(math.Round(total))
+	deg := sec / perDegree
+	min := sec % perDegree / perMinute
+	sec %= perMinute
+	if deg > math.MaxUint32 || sec > math.MaxUint32 {
+		return nil, fmt.Errorf("%w: %g degrees with precision %d", ErrValueOutOfRange, degrees, precision)
+	}
+
+	return []UnsignedRational{
+		{Numerator: uint32(deg), Denominator: 1},
+		{Numerator: uint32(min), Denominator: 1},
+		reduceRational(uint32(sec), precision),
+	}, nil
+}
+
+// durationToHMS converts a time of day to the hours, minutes and seconds
+// rationals of EXIF_TAG_GPS_TIME_STAMP.
+func durationToHMS(d time.Duration, precision uint32) ([]UnsignedRational, error) {
+	if d < 0 || d >= 24*time.Hour {
+		return nil, fmt.Errorf("%w: time of day %s", ErrValueOutOfRange, d)
+	}
+
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	d -= m * time.Minute
+
+	s, err := toRational(d.Seconds(), precision)
+	if err != nil {
+		return nil, err
+	}
+	return []UnsignedRational{
+		{Numerator: uint32(h), Denominator: 1},
+		{Numerator: uint32(m), Denominator: 1},
+		s,
+	}, nil
+}
+
+// toRational approximates a non negative value with the given denominator.
+func toRational(f float64, precision uint32) (UnsignedRational, error) {
+	num := math.Round(f * float64(precision))
+	if precision == 0 || f < 0 || math.IsNaN(num) || num > math.MaxUint32 {
+		return UnsignedRational{}, fmt.Errorf("%w: %g with precision %d", ErrValueOutOfRange, f, precision)
+	}
+	return reduceRational(uint32(num), precision), nil
+}
+
+func reduceRational(num, den uint32) UnsignedRational {
+	a, b := num, den
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a == 0 {
+		return UnsignedRational{Numerator: 0, Denominator: 1}
+	}
+	return UnsignedRational{Numerator: num / a, Denominator: den / a}
+}
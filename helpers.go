@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"time"
 )
 
 var (
@@ -28,77 +27,58 @@ func NewHelper(data *Data) *Helper {
 }
 
 func (h *Helper) GetLatitude() (float64, error) {
-	v, err := h.GetValue(IfdGps, EXIF_TAG_GPS_LATITUDE)
+	return h.getCoordinate(EXIF_TAG_GPS_LATITUDE, EXIF_TAG_GPS_LATITUDE_REF, 'S')
+}
+
+func (h *Helper) GetLongitude() (float64, error) {
+	return h.getCoordinate(EXIF_TAG_GPS_LONGITUDE, EXIF_TAG_GPS_LONGITUDE_REF, 'W')
+}
+
+// getCoordinate reads a degrees, minutes, seconds GPS tag as decimal degrees,
+// negated when its reference tag starts with negRef.
+func (h *Helper) getCoordinate(tag, refTag Tag, negRef byte) (float64, error) {
+	out, err := h.getDegrees(tag)
 	if err != nil {
 		return 0, err
 	}
 
-	rs, ok := v.([]UnsignedRational)
-	if !ok {
-		return 0, ErrValueNotMatch
-	}
-	if len(rs) != 3 {
-		return 0, ErrLengthNotMatch
-	}
-
-	deg := float64(rs[0].Numerator) / float64(rs[0].Denominator)
-	min := float64(rs[1].Numerator) / float64(rs[1].Denominator)
-	sec := float64(rs[2].Numerator) / float64(rs[2].Denominator)
-	out := deg + min/60.0 + sec/3600.0
-
-	refV, err := h.GetValue(IfdGps, EXIF_TAG_GPS_LATITUDE_REF)
+	refV, err := h.GetValue(IfdGps, refTag)
 	if err != nil {
 		return 0, err
 	}
 	ref, ok := refV.(string)
 	if !ok {
-		return 0, ErrValueNotMatch
+		return 0, h.entryError(IfdGps, refTag, FormatAscii, ErrValueNotMatch)
 	}
 	if len(ref) == 0 {
-		return 0, ErrValueTooSmall
+		return 0, h.entryError(IfdGps, refTag, FormatAscii, ErrValueTooSmall)
 	}
-	if strings.ToUpper(ref[:1])[0] == 'S' {
+	if strings.ToUpper(ref[:1])[0] == negRef {
 		out = -1 * out
 	}
 
 	return out, nil
 }
 
-func (h *Helper) GetLongitude() (float64, error) {
-	v, err := h.GetValue(IfdGps, EXIF_TAG_GPS_LONGITUDE)
+// getDegrees reads a degrees, minutes, seconds GPS tag as decimal degrees.
+func (h *Helper) getDegrees(tag Tag) (float64, error) {
+	v, err := h.GetValue(IfdGps, tag)
 	if err != nil {
 		return 0, err
 	}
 
 	rs, ok := v.([]UnsignedRational)
 	if !ok {
-		return 0, ErrValueNotMatch
+		return 0, h.entryError(IfdGps, tag, FormatUnsignedRational, ErrValueNotMatch)
 	}
 	if len(rs) != 3 {
-		return 0, ErrLengthNotMatch
+		return 0, h.entryError(IfdGps, tag, FormatUnsignedRational, ErrLengthNotMatch)
 	}
 
 	deg := float64(rs[0].Numerator) / float64(rs[0].Denominator)
 	min := float64(rs[1].Numerator) / float64(rs[1].Denominator)
 	sec := float64(rs[2].Numerator) / float64(rs[2].Denominator)
-	out := deg + min/60.0 + sec/3600.0
-
-	refV, err := h.GetValue(IfdGps, EXIF_TAG_GPS_LONGITUDE_REF)
-	if err != nil {
-		return 0, err
-	}
-	ref, ok := refV.(string)
-	if !ok {
-		return 0, ErrValueNotMatch
-	}
-	if len(ref) == 0 {
-		return 0, ErrValueTooSmall
-	}
-	if strings.ToUpper(ref[:1])[0] == 'W' {
-		out = -1 * out
-	}
-
-	return out, nil
+	return deg + min/60.0 + sec/3600.0, nil
 }
 
 func (h *Helper) GetAltitude() (float64, error) {
@@ -109,10 +89,10 @@ func (h *Helper) GetAltitude() (float64, error) {
 
 	rs, ok := v.([]UnsignedRational)
 	if !ok {
-		return 0, ErrValueNotMatch
+		return 0, h.entryError(IfdGps, EXIF_TAG_GPS_ALTITUDE, FormatUnsignedRational, ErrValueNotMatch)
 	}
 	if len(rs) == 0 {
-		return 0, ErrLengthNotMatch
+		return 0, h.entryError(IfdGps, EXIF_TAG_GPS_ALTITUDE, FormatUnsignedRational, ErrLengthNotMatch)
 	}
 	r := rs[0]
 
@@ -124,10 +104,10 @@ func (h *Helper) GetAltitude() (float64, error) {
 	}
 	ref, ok := refV.([]byte)
 	if !ok {
-		return 0, ErrValueNotMatch
+		return 0, h.entryError(IfdGps, EXIF_TAG_GPS_ALTITUDE_REF, FormatUnsignedByte, ErrValueNotMatch)
 	}
 	if len(ref) == 0 {
-		return 0, ErrValueTooSmall
+		return 0, h.entryError(IfdGps, EXIF_TAG_GPS_ALTITUDE_REF, FormatUnsignedByte, ErrValueTooSmall)
 	}
 	if ref[0] == 1 {
 		val = -1 * val
@@ -171,10 +151,6 @@ func (h *Helper) GetLocation() (*Location, error) {
 	}, nil
 }
 
-func (h *Helper) GetTimestamp() (*time.Time, error) {
-	return nil, nil
-}
-
 func (h *Helper) GetEntry(ifd, tag uint16) *Entry {
 	key := NewIfdTag(ifd, tag)
 	v, ok := h.Raw[key]
@@ -202,8 +178,35 @@ FormatDouble => []float64
 func (h *Helper) GetValue(ifd Ifd, tag Tag) (interface{}, error) {
 	entry := h.GetEntry(uint16(ifd), uint16(tag))
 	if entry == nil {
-		return nil, ErrNotFoundEntry
+		return nil, h.entryError(ifd, tag, 0, ErrNotFoundEntry)
 	}
 
 	return entry.GetValue()
 }
+
+// entryError returns an EntryError about reading the entry tag of ifd as
+// want, or as its own format when want is 0.
+func (h *Helper) entryError(ifd Ifd, tag Tag, want EntryFormat, err error) error {
+	if e := h.GetEntry(uint16(ifd), uint16(tag)); e != nil {
+		if want == 0 {
+			want = e.Format
+		}
+		return e.newError(want, err)
+	}
+	return &EntryError{Ifd: ifd, Tag: tag, Want: want, Err: err}
+}
+
+// GetString returns the value of an ASCII entry without its trailing NUL
+// bytes and spaces.
+func (h *Helper) GetString(ifd Ifd, tag Tag) (string, error) {
+	v, err := h.GetValue(ifd, tag)
+	if err != nil {
+		return "", err
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return "", h.entryError(ifd, tag, FormatAscii, ErrValueNotMatch)
+	}
+	return strings.TrimRight(s, "\x00 "), nil
+}
@@ -0,0 +1,225 @@
+package exif
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTimestamp(t *testing.T) {
+	data, err := Read("_examples/resources/testlocation.jpg")
+	require.NoError(t, err)
+
+	helper := NewHelper(data)
+	ts, err := helper.GetTimestamp()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2014, 4, 27, 18, 15, 4, 0, time.UTC), *ts)
+
+	dt, err := helper.GetDateTime()
+	require.NoError(t, err)
+	assert.False(t, dt.ZoneKnown)
+	assert.Equal(t, EXIF_TAG_DATE_TIME_ORIGINAL, dt.Tag)
+}
+
+func TestGetDateTimeZone(t *testing.T) {
+	data := New()
+	require.NoError(t, data.Set(Ifd0, EXIF_TAG_DATE_TIME, "2020:01:02 03:04:05"))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_SUB_SEC_TIME, "25"))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_OFFSET_TIME, "-05:30"))
+
+	helper := NewHelper(data)
+	dt, err := helper.GetDateTime()
+	require.NoError(t, err)
+	assert.True(t, dt.ZoneKnown)
+	assert.Equal(t, EXIF_TAG_DATE_TIME, dt.Tag)
+	want := time.Date(2020, 1, 2, 8, 34, 5, 250000000, time.UTC)
+	assert.True(t, want.Equal(dt.Time), dt.Time.String())
+
+	// Blank dates are skipped.
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_DATE_TIME_ORIGINAL, "    :  :     :  :  "))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_DATE_TIME_DIGITIZED, "2020:01:02 03:00:00"))
+	require.NoError(t, data.Set(Ifd0, EXIF_TAG_TIME_ZONE_OFFSET, []int16{0, 9}))
+	dt, err = helper.GetDateTime()
+	require.NoError(t, err)
+	assert.True(t, dt.ZoneKnown)
+	assert.Equal(t, EXIF_TAG_DATE_TIME_DIGITIZED, dt.Tag)
+	_, offset := dt.Time.Zone()
+	assert.Equal(t, 9*3600, offset)
+
+	data.Delete(Ifd0, EXIF_TAG_DATE_TIME)
+	data.Delete(IfdExif, EXIF_TAG_DATE_TIME_DIGITIZED)
+	_, err = helper.GetDateTime()
+	assert.True(t, errors.Is(err, ErrNotFoundEntry))
+	var entryErr *EntryError
+	require.True(t, errors.As(err, &entryErr))
+	assert.Equal(t, EXIF_TAG_DATE_TIME_ORIGINAL, entryErr.Tag)
+}
+
+func TestParseOffset(t *testing.T) {
+	offset, err := ParseOffset("+09:30")
+	require.NoError(t, err)
+	assert.Equal(t, 9*3600+30*60, offset)
+
+	offset, err = ParseOffset("-03:00")
+	require.NoError(t, err)
+	assert.Equal(t, -3*3600, offset)
+
+	offset, err = ParseOffset("+14:00")
+	require.NoError(t, err)
+	assert.Equal(t, 14*3600, offset)
+
+	offset, err = ParseOffset("-12:00")
+	require.NoError(t, err)
+	assert.Equal(t, -12*3600, offset)
+
+	for _, s := range []string{"", "09:00", "+9:00", "+09:60", "   :  ", "+15:00", "-99:00", "+-1:00", "+09:-1",
+		"-13:00", "-14:00", "-13:30", "+14:01", "+14:59", "++1:00"} {
+		_, err = ParseOffset(s)
+		assert.Equal(t, ErrInvalidOffset, err, s)
+	}
+}
+
+func TestGetGPSTime(t *testing.T) {
+	data, err := Read("_examples/resources/testlocation.jpg")
+	require.NoError(t, err)
+
+	helper := NewHelper(data)
+	gps, err := helper.GetGPSTime()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2014, 4, 27, 8, 44, 31, 0, time.UTC), gps)
+
+	// Taken at Uluru, Australian Central Standard Time.
+	offset, err := helper.InferTimezoneOffset()
+	require.NoError(t, err)
+	assert.Equal(t, 9*time.Hour+30*time.Minute, offset)
+}
+
+func TestGetGPSInfo(t *testing.T) {
+	data, err := Read("_examples/resources/testlocation.jpg")
+	require.NoError(t, err)
+
+	info, err := NewHelper(data).GetGPSInfo()
+	require.NoError(t, err)
+	require.NotNil(t, info.Latitude)
+	assert.InDelta(t, -25.35905836, *info.Latitude, 1e-8)
+	require.NotNil(t, info.Longitude)
+	assert.InDelta(t, 131.01533508, *info.Longitude, 1e-8)
+	require.NotNil(t, info.Altitude)
+	assert.Equal(t, 492.0, *info.Altitude)
+	require.NotNil(t, info.ImgDirection)
+	assert.Equal(t, 303.0, *info.ImgDirection)
+	assert.Equal(t, "M", *info.ImgDirectionRef)
+	assert.Equal(t, "FUSED", *info.ProcessingMethod)
+	assert.Equal(t, 8*time.Hour+44*time.Minute+31*time.Second, *info.TimeStamp)
+	assert.Nil(t, info.Speed)
+	assert.Nil(t, info.MapDatum)
+
+	// A missing altitude only leaves that field empty.
+	data.Delete(IfdGps, EXIF_TAG_GPS_ALTITUDE)
+	info, err = NewHelper(data).GetGPSInfo()
+	require.NoError(t, err)
+	assert.Nil(t, info.Altitude)
+	assert.NotNil(t, info.Latitude)
+
+	_, err = NewHelper(New()).GetGPSInfo()
+	assert.True(t, errors.Is(err, ErrNotFoundEntry))
+}
+
+func TestSetLocation(t *testing.T) {
+	data := New()
+	loc := Location{Latitude: -25.35905836, Longitude: 131.01533508, Altitude: -12.5}
+	require.NoError(t, data.SetLocation(loc))
+
+	helper := NewHelper(data)
+	got, err := helper.GetLocation()
+	require.NoError(t, err)
+	assert.InDelta(t, loc.Latitude, got.Latitude, 1e-8)
+	assert.InDelta(t, loc.Longitude, got.Longitude, 1e-8)
+	assert.Equal(t, loc.Altitude, got.Altitude)
+
+	v, err := helper.GetValue(IfdGps, EXIF_TAG_GPS_VERSION_ID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{2, 3, 0, 0}, v)
+	assert.NotNil(t, helper.GetEntry(uint16(Ifd0), uint16(EXIF_TAG_GPS_INFO_IFD_POINTER)))
+
+	data.GPSPrecision = 100
+	require.NoError(t, data.SetLocation(Location{Latitude: 10.5, Longitude: -0.000001}))
+	v, err = helper.GetValue(IfdGps, EXIF_TAG_GPS_LATITUDE)
+	require.NoError(t, err)
+	assert.Equal(t, []UnsignedRational{{10, 1}, {30, 1}, {0, 1}}, v)
+	ref, err := helper.GetString(IfdGps, EXIF_TAG_GPS_LONGITUDE_REF)
+	require.NoError(t, err)
+	assert.Equal(t, "W", ref)
+}
+
+func TestSetGPSInfo(t *testing.T) {
+	src, err := Read("_examples/resources/testlocation.jpg")
+	require.NoError(t, err)
+	info, err := NewHelper(src).GetGPSInfo()
+	require.NoError(t, err)
+
+	data := New()
+	require.NoError(t, data.SetGPSInfo(info))
+	got, err := NewHelper(data).GetGPSInfo()
+	require.NoError(t, err)
+
+	assert.InDelta(t, *info.Latitude, *got.Latitude, 1e-8)
+	assert.InDelta(t, *info.Longitude, *got.Longitude, 1e-8)
+	assert.Equal(t, []byte{2, 3, 0, 0}, got.VersionID)
+	got.Latitude, got.Longitude, got.VersionID = info.Latitude, info.Longitude, info.VersionID
+	assert.Equal(t, info, got)
+}
+
+func TestSetGPSInfoOutOfRange(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	clock := -time.Minute
+
+	for name, info := range map[string]*GPSInfo{
+		"latitude":       {Latitude: f(90.5)},
+		"longitude":      {Latitude: f(10), Longitude: f(-181)},
+		"nan":            {Latitude: f(math.NaN())},
+		"inf":            {Longitude: f(math.Inf(1))},
+		"negative speed": {Latitude: f(10), Speed: f(-3)},
+		"negative track": {Latitude: f(10), Track: f(-0.0001)},
+		"time stamp":     {Latitude: f(10), TimeStamp: &clock},
+	} {
+		data := New()
+		err := data.SetGPSInfo(info)
+		assert.True(t, errors.Is(err, ErrValueOutOfRange), "%s: %v", name, err)
+		assert.Empty(t, data.Entries, name)
+	}
+
+	data := New()
+	data.GPSPrecision = 1e6
+	err := data.SetLocation(Location{Latitude: 27.98785, Longitude: 86.9250, Altitude: 8848})
+	assert.True(t, errors.Is(err, ErrValueOutOfRange))
+	assert.Empty(t, data.Entries)
+
+	// The seconds do not fit with a precision above 2^32/60.
+	data.GPSPrecision = 1e8
+	err = data.SetLocation(Location{Latitude: 45.99999})
+	assert.True(t, errors.Is(err, ErrValueOutOfRange))
+	assert.Empty(t, data.Entries)
+}
+
+func TestDegreesToDMS(t *testing.T) {
+	dms := func(degrees float64, precision uint32) []UnsignedRational {
+		v, err := DegreesToDMS(degrees, precision)
+		require.NoError(t, err)
+		return v
+	}
+	// Rounding the seconds carries over to the minutes and degrees.
+	assert.Equal(t, []UnsignedRational{{13, 1}, {0, 1}, {0, 1}}, dms(12.9999999999, 100))
+	assert.Equal(t, []UnsignedRational{{25, 1}, {21, 1}, {326101, 10000}}, dms(-25.359058361111, 10000))
+
+	for _, v := range []float64{math.NaN(), math.Inf(-1), 1e300} {
+		_, err := DegreesToDMS(v, 100)
+		assert.True(t, errors.Is(err, ErrValueOutOfRange), "%g", v)
+	}
+	_, err := DegreesToDMS(10, 0)
+	assert.True(t, errors.Is(err, ErrValueOutOfRange))
+}
@@ -0,0 +1,80 @@
+package exif
+
+// IfdInfo describes where an IFD is in the tree of IFDs of a file.
+//
+// Besides the IFDs libexif knows, files may hold IFDs listed by a SubIFDs
+// entry, as DNG and TIFF-EP files do for full resolution images, IFDs chained
+// after IFD1, and private IFDs pointed to by entries of the TIFF IFD format.
+// These are numbered from IfdMaxCount in the order they are met, and their
+// tags are named as in IFD0. They are not written by Save.
+type IfdInfo struct {
+	Ifd Ifd
+	// Parent is the IFD holding the entry Tag that points to this one, or,
+	// when Tag is 0, the IFD whose next IFD link points to this one. IFD0 is
+	// its own parent.
+	Parent Ifd
+	Tag    Tag
+	// Index is the position of the IFD among the ones listed by Tag.
+	Index int
+	// Children are the IFDs this one points to.
+	Children []Ifd
+}
+
+// Ifds lists the IFDs of the file, parents before their children, along
+// with the IFDs libexif knows that were added since.
+func (d *Data) Ifds() []IfdInfo {
+	out := make([]IfdInfo, 0, len(d.ifds))
+	listed := make(map[Ifd]int)
+	add := func(info IfdInfo) {
+		info.Children = append([]Ifd(nil), info.Children...)
+		if i, ok := listed[info.Parent]; ok && info.Ifd != Ifd0 && !info.hasParent(out[i]) {
+			out[i].Children = append(out[i].Children, info.Ifd)
+		}
+		listed[info.Ifd] = len(out)
+		out = append(out, info)
+	}
+
+	for _, info := range d.ifds {
+		add(info)
+	}
+	for ifd := Ifd0; ifd < IfdMaxCount; ifd++ {
+		if _, ok := listed[ifd]; ok || d.ifdEmpty(ifd) {
+			continue
+		}
+		info := IfdInfo{Ifd: ifd, Parent: Ifd0}
+		if ptr, ok := ifdPointers[ifd]; ok {
+			info.Parent, info.Tag = ptr.parent, ptr.tag
+		}
+		add(info)
+	}
+
+	return out
+}
+
+// hasParent reports whether parent lists i as a child.
+func (i IfdInfo) hasParent(parent IfdInfo) bool {
+	for _, child := range parent.Children {
+		if child == i.Ifd {
+			return true
+		}
+	}
+	return false
+}
+
+// ifdInfo returns the description of ifd, or nil when it was not read.
+func (d *Data) ifdInfo(ifd Ifd) *IfdInfo {
+	for i := range d.ifds {
+		if d.ifds[i].Ifd == ifd {
+			return &d.ifds[i]
+		}
+	}
+	return nil
+}
+
+// tableIfd returns the IFD whose tags ifd uses.
+func (ifd Ifd) tableIfd() Ifd {
+	if ifd >= IfdMaxCount {
+		return Ifd0
+	}
+	return ifd
+}
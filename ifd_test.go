@@ -0,0 +1,80 @@
+package exif
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubIfds(t *testing.T) {
+	private := Tag(0xc000)
+
+	// IFD0 lists two SubIFDs, IFD1 is followed by IFD2, which points to a
+	// private IFD and links back to IFD1.
+	src := newTiffBytes(binary.LittleEndian).u16(2).
+		short(EXIF_TAG_ORIENTATION, 1).
+		entry(EXIF_TAG_SUB_IFDS, FormatUnsignedLong, 2, 38).u32(82).
+		u32(46).u32(64).
+		u16(1).short(EXIF_TAG_IMAGE_WIDTH, 4000).u32(0).
+		u16(1).short(EXIF_TAG_IMAGE_WIDTH, 160).u32(0).
+		u16(1).short(EXIF_TAG_COMPRESSION, 6).u32(100).
+		u16(2).short(EXIF_TAG_IMAGE_WIDTH, 10).
+		entry(private, formatIfd, 1, 130).u32(82).
+		u16(1).short(0x9999, 7).u32(0).jpeg()
+
+	data, err := ReadBytes(src)
+	require.NoError(t, err)
+
+	sub, sub2, ifd2, priv := IfdMaxCount, IfdMaxCount+1, IfdMaxCount+2, IfdMaxCount+3
+	want := []IfdInfo{
+		{Ifd: Ifd0, Parent: Ifd0, Children: []Ifd{sub, sub2, Ifd1}},
+		{Ifd: sub, Parent: Ifd0, Tag: EXIF_TAG_SUB_IFDS},
+		{Ifd: sub2, Parent: Ifd0, Tag: EXIF_TAG_SUB_IFDS, Index: 1},
+		{Ifd: Ifd1, Parent: Ifd0, Children: []Ifd{ifd2}},
+		{Ifd: ifd2, Parent: Ifd1, Children: []Ifd{priv}},
+		{Ifd: priv, Parent: ifd2, Tag: private},
+	}
+	if libexifFixes {
+		// libexif adds the mandatory EXIF entries.
+		want[0].Children = append(want[0].Children, IfdExif)
+		want = append(want, IfdInfo{Ifd: IfdExif, Parent: Ifd0, Tag: EXIF_TAG_EXIF_IFD_POINTER})
+	}
+	assert.Equal(t, want, data.Ifds())
+
+	for ifd, want := range map[Ifd]uint16{sub: 4000, sub2: 160, ifd2: 10} {
+		e, ok := data.Raw[NewIfdTag(uint16(ifd), uint16(EXIF_TAG_IMAGE_WIDTH))]
+		require.True(t, ok, ifd.String())
+		v, err := e.ReadAsUnsignedShort()
+		require.NoError(t, err)
+		assert.Equal(t, []uint16{want}, v, ifd.String())
+	}
+	_, ok := data.Raw[NewIfdTag(uint16(priv), 0x9999)]
+	assert.True(t, ok)
+	assert.Equal(t, "ImageWidth", EXIF_TAG_IMAGE_WIDTH.Name(sub))
+	assert.Equal(t, priv, data.Entries[len(data.Entries)-1].Ifd)
+
+	l, err := data.Layout()
+	require.NoError(t, err)
+	assert.Len(t, l.Ifds, 6)
+
+	// The other IFDs are not written.
+	assert.False(t, TagAllowed(sub, EXIF_TAG_IMAGE_WIDTH))
+	block, err := data.marshal()
+	require.NoError(t, err)
+	saved, err := ReadBytes(block)
+	require.NoError(t, err)
+	for _, info := range saved.Ifds() {
+		assert.Less(t, info.Ifd, IfdMaxCount)
+	}
+
+	// IFDs added with Set are listed too.
+	data = New()
+	require.NoError(t, data.Set(IfdInterOperability, EXIF_TAG_INTEROPERABILITY_INDEX, "R98"))
+	assert.Equal(t, []IfdInfo{
+		{Ifd: Ifd0, Parent: Ifd0, Children: []Ifd{IfdExif}},
+		{Ifd: IfdExif, Parent: Ifd0, Tag: EXIF_TAG_EXIF_IFD_POINTER, Children: []Ifd{IfdInterOperability}},
+		{Ifd: IfdInterOperability, Parent: IfdExif, Tag: EXIF_TAG_INTEROPERABILITY_IFD_POINTER},
+	}, data.Ifds())
+}
@@ -0,0 +1,202 @@
+package exif
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RegionKind tells what a region of the EXIF block holds.
+type RegionKind int
+
+// Region kinds.
+const (
+	RegionHeader RegionKind = iota
+	RegionIfd
+	RegionValue
+	RegionThumbnail
+)
+
+// Region is a range of bytes of the EXIF block.
+type Region struct {
+	Kind RegionKind
+	// Ifd is the IFD the region belongs to, and Tag the entry of a value.
+	Ifd    Ifd
+	Tag    Tag
+	Offset int
+	Length int
+}
+
+func (r Region) String() string {
+	switch r.Kind {
+	case RegionHeader:
+		return "TIFF header"
+	case RegionIfd:
+		return fmt.Sprintf("%s directory", r.Ifd)
+	case RegionValue:
+		return fmt.Sprintf("%s %s value", r.Ifd, tagLabel(r.Ifd, r.Tag))
+	case RegionThumbnail:
+		return fmt.Sprintf("%s thumbnail", r.Ifd)
+	}
+	return fmt.Sprintf("region %d", int(r.Kind))
+}
+
+// IfdLayout is the position of an IFD.
+type IfdLayout struct {
+	Ifd Ifd
+	// Offset is the position of the entry count, and Entries the count.
+	Offset  int
+	Entries int
+	// Next is the position of the next IFD, 0 when there is none.
+	Next int
+}
+
+// LayoutIssueKind tells what is wrong with a range of the EXIF block.
+type LayoutIssueKind int
+
+const (
+	// LayoutOverlap is a range used by two regions.
+	LayoutOverlap LayoutIssueKind = iota
+	// LayoutOutOfBounds is a region extending past the end of the block.
+	LayoutOutOfBounds
+	// LayoutGap is a range between two regions that none uses. The padding
+	// byte aligning a region on a word boundary is not a gap.
+	LayoutGap
+	// LayoutTrailing is data following the last region.
+	LayoutTrailing
+)
+
+// LayoutIssue is a range of the EXIF block that is not laid out as expected.
+type LayoutIssue struct {
+	Kind   LayoutIssueKind
+	Offset int
+	Length int
+	// Regions are the regions involved, none for gaps and trailing data.
+	Regions []Region
+}
+
+func (i LayoutIssue) String() string {
+	switch i.Kind {
+	case LayoutOverlap:
+		return fmt.Sprintf("%d bytes at %d used by both %s and %s", i.Length, i.Offset, i.Regions[0], i.Regions[1])
+	case LayoutOutOfBounds:
+		return fmt.Sprintf("%s at %d (%d bytes) is out of bounds", i.Regions[0], i.Offset, i.Length)
+	case LayoutGap:
+		return fmt.Sprintf("%d unused bytes at %d", i.Length, i.Offset)
+	case LayoutTrailing:
+		return fmt.Sprintf("%d bytes of trailing data at %d", i.Length, i.Offset)
+	}
+	return fmt.Sprintf("issue %d at %d", int(i.Kind), i.Offset)
+}
+
+// Layout describes where the parts of the EXIF block are. Offsets are
+// positions in the data read: the file given to Open, or the bytes given to
+// ReadBytes.
+type Layout struct {
+	// Offset is the position of the TIFF header, and Size the length of the
+	// TIFF data up to the end of the EXIF block.
+	Offset int
+	Size   int
+
+	Ifds []IfdLayout
+	// Regions are sorted by offset.
+	Regions []Region
+	Issues  []LayoutIssue
+}
+
+// Layout walks the EXIF block read again and reports where its parts are,
+// including the entries dropped when loading. It returns ErrNoExifData when
+// no block was read.
+func (d *Data) Layout() (*Layout, error) {
+	r, err := newTiffReader(d.rawExif, false)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Layout{Size: len(r.b)}
+	r.layout = l
+	r.read()
+
+	l.check()
+	l.rebase(d.exifOffset + len(exifHeader))
+	return l, nil
+}
+
+// check sorts the regions and lists the issues. Offsets are relative to the
+// TIFF header.
+func (l *Layout) check() {
+	sort.SliceStable(l.Regions, func(i, j int) bool {
+		return l.Regions[i].Offset < l.Regions[j].Offset
+	})
+
+	// end is where the regions seen so far end, last the region ending there.
+	end := 0
+	var last Region
+	for _, region := range l.Regions {
+		start, length := region.Offset, region.Length
+		if start >= l.Size || length > l.Size-start {
+			l.Issues = append(l.Issues, LayoutIssue{
+				Kind:    LayoutOutOfBounds,
+				Offset:  start,
+				Length:  length,
+				Regions: []Region{region},
+			})
+			if start >= l.Size {
+				continue
+			}
+			length = l.Size - start
+		}
+
+		switch {
+		case start < end:
+			overlap := end - start
+			if length < overlap {
+				overlap = length
+			}
+			if overlap > 0 {
+				l.Issues = append(l.Issues, LayoutIssue{
+					Kind:    LayoutOverlap,
+					Offset:  start,
+					Length:  overlap,
+					Regions: []Region{last, region},
+				})
+			}
+		case start > end && !isPadding(end, start):
+			l.Issues = append(l.Issues, LayoutIssue{Kind: LayoutGap, Offset: end, Length: start - end})
+		}
+
+		if start+length > end {
+			end = start + length
+			last = region
+		}
+	}
+
+	if end < l.Size && !isPadding(end, l.Size) {
+		l.Issues = append(l.Issues, LayoutIssue{Kind: LayoutTrailing, Offset: end, Length: l.Size - end})
+	}
+}
+
+// isPadding reports whether the range from start to end is the byte aligning
+// end on a word boundary.
+func isPadding(start, end int) bool {
+	return end-start == 1 && end%2 == 0
+}
+
+// rebase moves the offsets of l by base.
+func (l *Layout) rebase(base int) {
+	l.Offset += base
+	for i := range l.Ifds {
+		l.Ifds[i].Offset += base
+		if l.Ifds[i].Next != 0 {
+			l.Ifds[i].Next += base
+		}
+	}
+	for i := range l.Regions {
+		l.Regions[i].Offset += base
+	}
+	for i := range l.Issues {
+		l.Issues[i].Offset += base
+		for j := range l.Issues[i].Regions {
+			l.Issues[i].Regions[j].Offset += base
+		}
+	}
+}
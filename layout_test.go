@@ -0,0 +1,68 @@
+package exif
+
+import (
+	"encoding/binary"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayout(t *testing.T) {
+	src, err := os.ReadFile("_examples/resources/test.jpg")
+	require.NoError(t, err)
+	data, err := ReadBytes(src)
+	require.NoError(t, err)
+
+	l, err := data.Layout()
+	require.NoError(t, err)
+	for _, issue := range l.Issues {
+		assert.NotEqual(t, LayoutOverlap, issue.Kind, issue.String())
+		assert.NotEqual(t, LayoutOutOfBounds, issue.Kind, issue.String())
+	}
+	require.NotEmpty(t, l.Ifds)
+	assert.Equal(t, Ifd0, l.Ifds[0].Ifd)
+	assert.Equal(t, "II", string(src[l.Offset:l.Offset+2]))
+
+	// Offsets point to the values in the file.
+	maker := data.Raw[NewIfdTag(uint16(Ifd0), uint16(EXIF_TAG_MAKE))]
+	require.NotZero(t, maker.Offset)
+	assert.Equal(t, maker.Raw, src[maker.Offset:maker.Offset+maker.Length])
+
+	// A value left unused, two values sharing bytes, a value out of bounds
+	// and data after the last value.
+	src = newTiffBytes(binary.LittleEndian).u16(3).
+		entry(EXIF_TAG_MAKE, FormatAscii, 8, 52).
+		entry(EXIF_TAG_MODEL, FormatAscii, 8, 56).
+		entry(EXIF_TAG_ARTIST, FormatAscii, 100, 0x1000).u32(0).
+		bytes(0, 0).bytes([]byte("ACME\x00BOX\x00\x00\x00\x00")...).
+		bytes([]byte("JUNK")...).jpeg()
+	data, err = ReadBytes(src)
+	require.NoError(t, err)
+
+	l, err = data.Layout()
+	require.NoError(t, err)
+	base := l.Offset
+	assert.Equal(t, 12, base)
+	assert.Equal(t, []IfdLayout{{Ifd: Ifd0, Offset: base + 8, Entries: 3}}, l.Ifds)
+
+	var kinds []LayoutIssueKind
+	for _, issue := range l.Issues {
+		kinds = append(kinds, issue.Kind)
+	}
+	require.Equal(t, []LayoutIssueKind{LayoutGap, LayoutOverlap, LayoutOutOfBounds, LayoutTrailing}, kinds)
+	assert.Equal(t, LayoutIssue{Kind: LayoutGap, Offset: base + 50, Length: 2}, l.Issues[0])
+	assert.Equal(t, base+56, l.Issues[1].Offset)
+	assert.Equal(t, 4, l.Issues[1].Length)
+	assert.Equal(t, EXIF_TAG_MODEL, l.Issues[1].Regions[1].Tag)
+	assert.Equal(t, EXIF_TAG_ARTIST, l.Issues[2].Regions[0].Tag)
+	assert.Equal(t, LayoutIssue{Kind: LayoutTrailing, Offset: base + 64, Length: 4}, l.Issues[3])
+
+	model := data.Raw[NewIfdTag(uint16(Ifd0), uint16(EXIF_TAG_MODEL))]
+	assert.Equal(t, base+56, model.Offset)
+	assert.Equal(t, 8, model.Length)
+
+	_, err = New().Layout()
+	assert.Equal(t, ErrNoExifData, err)
+}
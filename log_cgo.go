@@ -0,0 +1,58 @@
+//go:build cgo
+
+package exif
+
+/*
+#include <stdint.h>
+#include <libexif/exif-data.h>
+#include <libexif/exif-log.h>
+
+ExifLog* exif_log_new_go(uintptr_t);
+*/
+import "C"
+
+import (
+	"runtime/cgo"
+	"strings"
+)
+
+// exifLog collects the messages libexif logs as diagnostics.
+type exifLog struct {
+	log         *C.ExifLog
+	handle      cgo.Handle
+	diagnostics []Diagnostic
+}
+
+func newExifLog() (*exifLog, error) {
+	l := &exifLog{}
+	l.handle = cgo.NewHandle(l)
+	l.log = C.exif_log_new_go(C.uintptr_t(l.handle))
+	if l.log == nil {
+		l.handle.Delete()
+		return nil, ErrNoMemory
+	}
+	return l, nil
+}
+
+// attach makes ed, and the maker note it loads, log to l.
+func (l *exifLog) attach(ed *C.ExifData) {
+	C.exif_data_log(ed, l.log)
+}
+
+// free releases l once the ExifData it is attached to are released.
+func (l *exifLog) free() {
+	C.exif_log_unref(l.log)
+	l.handle.Delete()
+}
+
+//export goExifLog
+func goExifLog(handle C.uintptr_t, code C.ExifLogCode, domain, message *C.char) {
+	l := cgo.Handle(handle).Value().(*exifLog)
+	g := Diagnostic{
+		Code:    LogCode(code),
+		Domain:  C.GoString(domain),
+		Message: strings.TrimSpace(C.GoString(message)),
+	}
+	g.locate()
+	l.diagnostics = append(l.diagnostics, g)
+}
@@ -0,0 +1,214 @@
+package exif
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+)
+
+// Orientation is the value of the Orientation tag: where the first row and
+// the first column of the stored image are once it is displayed.
+type Orientation uint16
+
+// Orientations, named after the transform that displays the stored image.
+const (
+	OrientationNormal     Orientation = 1 // top-left
+	OrientationFlipH      Orientation = 2 // top-right
+	OrientationRotate180  Orientation = 3 // bottom-right
+	OrientationFlipV      Orientation = 4 // bottom-left
+	OrientationTranspose  Orientation = 5 // left-top
+	OrientationRotate90   Orientation = 6 // right-top, rotate clockwise
+	OrientationTransverse Orientation = 7 // right-bottom
+	OrientationRotate270  Orientation = 8 // left-bottom, rotate counterclockwise
+)
+
+var orientationNames = map[Orientation]string{
+	OrientationNormal:     "Top-left",
+	OrientationFlipH:      "Top-right",
+	OrientationRotate180:  "Bottom-right",
+	OrientationFlipV:      "Bottom-left",
+	OrientationTranspose:  "Left-top",
+	OrientationRotate90:   "Right-top",
+	OrientationTransverse: "Right-bottom",
+	OrientationRotate270:  "Left-bottom",
+}
+
+// String returns the name libexif gives the orientation.
+func (o Orientation) String() string {
+	if name, ok := orientationNames[o]; ok {
+		return name
+	}
+	return fmt.Sprintf("Orientation(%d)", uint16(o))
+}
+
+// Valid reports whether o is one of the 8 orientations.
+func (o Orientation) Valid() bool {
+	return o >= OrientationNormal && o <= OrientationRotate270
+}
+
+// swapsAxes reports whether displaying the image swaps its width and height.
+func (o Orientation) swapsAxes() bool {
+	return o >= OrientationTranspose && o <= OrientationRotate270
+}
+
+// GetOrientation returns the orientation recorded in IFD0.
+func (h *Helper) GetOrientation() (Orientation, error) {
+	v, err := h.getShort(Ifd0, EXIF_TAG_ORIENTATION)
+	if err != nil {
+		return 0, err
+	}
+
+	o := Orientation(v)
+	if !o.Valid() {
+		return 0, h.entryError(Ifd0, EXIF_TAG_ORIENTATION, FormatUnsignedShort, ErrValueNotMatch)
+	}
+	return o, nil
+}
+
+// ApplyOrientation returns img as it is meant to be displayed when its
+// orientation is o. img is returned as is for OrientationNormal and the
+// values that are not valid.
+func ApplyOrientation(img image.Image, o Orientation) image.Image {
+	if o == OrientationNormal || !o.Valid() {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if o.swapsAxes() {
+		dw, dh = h, w
+	}
+	out := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			// The pixel of img displayed at x, y.
+			var sx, sy int
+			switch o {
+			case OrientationFlipH:
+				sx, sy = w-1-x, y
+			case OrientationRotate180:
+				sx, sy = w-1-x, h-1-y
+			case OrientationFlipV:
+				sx, sy = x, h-1-y
+			case OrientationTranspose:
+				sx, sy = y, x
+			case OrientationRotate90:
+				sx, sy = y, h-1-x
+			case OrientationTransverse:
+				sx, sy = w-1-y, h-1-x
+			case OrientationRotate270:
+				sx, sy = w-1-y, x
+			}
+			out.Set(x, y, img.At(b.Min.X+sx, b.Min.Y+sy))
+		}
+	}
+	return out
+}
+
+// DecodeOriented decodes an image in any of the formats registered with the
+// image package, and turns it as its EXIF orientation says. The EXIF data is
+// returned along with the image, nil when the image has none.
+func DecodeOriented(r io.Reader) (image.Image, *Data, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := ReadBytes(b)
+	if err != nil && err != ErrNoExifData {
+		return nil, nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if data != nil {
+		if o, err := NewHelper(data).GetOrientation(); err == nil {
+			img = ApplyOrientation(img, o)
+		}
+	}
+	return img, data, nil
+}
+
+// NormalizeOrientation records that the image is stored the way it is meant
+// to be displayed, once its pixels have been turned as the orientation says.
+// The orientation is reset to OrientationNormal, the dimensions are swapped
+// when the image was rotated by a quarter turn, and the thumbnail is turned
+// the same way. An uncompressed thumbnail is replaced with a JPEG one, and a
+// thumbnail that cannot be decoded is removed, as it could not be turned.
+// Data without orientation is left as is.
+func (d *Data) NormalizeOrientation() error {
+	o, err := NewHelper(d).GetOrientation()
+	if errors.Is(err, ErrNotFoundEntry) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if o == OrientationNormal {
+		return nil
+	}
+
+	// The thumbnail is turned first, nothing is changed when it no longer
+	// fits.
+	img, err := d.ThumbnailImage()
+	switch {
+	case err == nil:
+		var buf bytes.Buffer
+		err = jpeg.Encode(&buf, ApplyOrientation(img, o), &jpeg.Options{Quality: ThumbnailQuality})
+		if err != nil {
+			return err
+		}
+		if err := d.SetThumbnail(buf.Bytes()); err != nil {
+			return err
+		}
+	case err != ErrNoThumbnail:
+		d.RemoveThumbnail()
+	}
+
+	if err := d.Set(Ifd0, EXIF_TAG_ORIENTATION, uint16(OrientationNormal)); err != nil {
+		return err
+	}
+	// Some cameras record the orientation of the thumbnail as well.
+	if NewHelper(d).GetEntry(uint16(Ifd1), uint16(EXIF_TAG_ORIENTATION)) != nil {
+		if err := d.Set(Ifd1, EXIF_TAG_ORIENTATION, uint16(OrientationNormal)); err != nil {
+			return err
+		}
+	}
+
+	if o.swapsAxes() {
+		d.swapEntries(Ifd0, EXIF_TAG_IMAGE_WIDTH, EXIF_TAG_IMAGE_LENGTH)
+		d.swapEntries(IfdExif, EXIF_TAG_PIXEL_X_DIMENSION, EXIF_TAG_PIXEL_Y_DIMENSION)
+	}
+	return nil
+}
+
+// swapEntries exchanges the values of tags a and b in ifd, keeping their
+// formats.
+func (d *Data) swapEntries(ifd Ifd, a, b Tag) {
+	ea, okA := d.Raw[NewIfdTag(uint16(ifd), uint16(a))]
+	eb, okB := d.Raw[NewIfdTag(uint16(ifd), uint16(b))]
+	if !okA {
+		d.Delete(ifd, b)
+	}
+	if !okB {
+		d.Delete(ifd, a)
+	}
+
+	// The values no longer are where the entries were read from.
+	if okA {
+		ea.Tag, ea.Offset, ea.Length = b, 0, 0
+		d.SetEntry(&ea)
+	}
+	if okB {
+		eb.Tag, eb.Offset, eb.Length = a, 0, 0
+		d.SetEntry(&eb)
+	}
+}
@@ -0,0 +1,194 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOrientation(t *testing.T) {
+	data := New()
+	_, err := NewHelper(data).GetOrientation()
+	assert.True(t, errors.Is(err, ErrNotFoundEntry))
+
+	require.NoError(t, data.Set(Ifd0, EXIF_TAG_ORIENTATION, uint16(6)))
+	o, err := NewHelper(data).GetOrientation()
+	require.NoError(t, err)
+	assert.Equal(t, OrientationRotate90, o)
+	assert.Equal(t, "Right-top", o.String())
+
+	require.NoError(t, data.Set(Ifd0, EXIF_TAG_ORIENTATION, uint16(9)))
+	_, err = NewHelper(data).GetOrientation()
+	assert.True(t, errors.Is(err, ErrValueNotMatch))
+	assert.Equal(t, "Orientation(9)", Orientation(9).String())
+}
+
+func TestApplyOrientation(t *testing.T) {
+	// 3x2 image, each pixel holding its position in the red channel.
+	src := image.NewRGBA(image.Rect(10, 20, 13, 22))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			src.SetRGBA(10+x, 20+y, color.RGBA{uint8(y*3 + x), 0, 0, 0xff})
+		}
+	}
+	pixels := func(img image.Image) (out [][]uint8) {
+		b := img.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			var row []uint8
+			for x := b.Min.X; x < b.Max.X; x++ {
+				r, _, _, _ := img.At(x, y).RGBA()
+				row = append(row, uint8(r>>8))
+			}
+			out = append(out, row)
+		}
+		return out
+	}
+
+	expected := map[Orientation][][]uint8{
+		OrientationNormal:     {{0, 1, 2}, {3, 4, 5}},
+		OrientationFlipH:      {{2, 1, 0}, {5, 4, 3}},
+		OrientationRotate180:  {{5, 4, 3}, {2, 1, 0}},
+		OrientationFlipV:      {{3, 4, 5}, {0, 1, 2}},
+		OrientationTranspose:  {{0, 3}, {1, 4}, {2, 5}},
+		OrientationRotate90:   {{3, 0}, {4, 1}, {5, 2}},
+		OrientationTransverse: {{5, 2}, {4, 1}, {3, 0}},
+		OrientationRotate270:  {{2, 5}, {1, 4}, {0, 3}},
+		Orientation(0):        {{0, 1, 2}, {3, 4, 5}},
+	}
+	for o, want := range expected {
+		assert.Equal(t, want, pixels(ApplyOrientation(src, o)), o.String())
+	}
+}
+
+func TestDecodeOriented(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 32, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			img.SetGray(x, y, color.Gray{0xff})
+		}
+	}
+	var src bytes.Buffer
+	require.NoError(t, jpeg.Encode(&src, img, nil))
+
+	// Rotated clockwise, the white half is at the top.
+	tiff := newTiffBytes(binary.BigEndian).u16(1).
+		short(EXIF_TAG_ORIENTATION, 6).u32(0).jpeg()
+	data, err := ReadBytes(tiff)
+	require.NoError(t, err)
+	var out bytes.Buffer
+	require.NoError(t, data.Save(&out, bytes.NewReader(src.Bytes())))
+
+	oriented, data, err := DecodeOriented(&out)
+	require.NoError(t, err)
+	require.NotNil(t, data)
+	assert.Equal(t, image.Rect(0, 0, 16, 32), oriented.Bounds())
+	top, _, _, _ := oriented.At(8, 4).RGBA()
+	bottom, _, _, _ := oriented.At(8, 28).RGBA()
+	assert.True(t, top > 0xf000 && bottom < 0x1000, "top %x, bottom %x", top, bottom)
+
+	// Images without EXIF data are decoded as is.
+	var p bytes.Buffer
+	require.NoError(t, png.Encode(&p, img))
+	oriented, data, err = DecodeOriented(&p)
+	require.NoError(t, err)
+	assert.Nil(t, data)
+	assert.Equal(t, img.Bounds(), oriented.Bounds())
+}
+
+func TestNormalizeOrientation(t *testing.T) {
+	// The left half of the image is white, it is at the top once rotated.
+	img := image.NewGray(image.Rect(0, 0, 64, 32))
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x++ {
+			img.SetGray(x, y, color.Gray{0xff})
+		}
+	}
+	var src bytes.Buffer
+	require.NoError(t, jpeg.Encode(&src, img, nil))
+
+	data := New()
+	require.NoError(t, data.Set(Ifd0, EXIF_TAG_ORIENTATION, uint16(OrientationRotate90)))
+	require.NoError(t, data.Set(Ifd0, EXIF_TAG_IMAGE_WIDTH, uint32(64)))
+	require.NoError(t, data.Set(Ifd0, EXIF_TAG_IMAGE_LENGTH, uint32(32)))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_PIXEL_X_DIMENSION, uint32(64)))
+	require.NoError(t, data.Set(IfdExif, EXIF_TAG_PIXEL_Y_DIMENSION, uint16(32)))
+	require.NoError(t, data.RegenerateThumbnail(img, 32))
+
+	require.NoError(t, data.NormalizeOrientation())
+
+	var out bytes.Buffer
+	require.NoError(t, data.Save(&out, bytes.NewReader(src.Bytes())))
+	data, err := ReadBytes(out.Bytes())
+	require.NoError(t, err)
+
+	h := NewHelper(data)
+	o, err := h.GetOrientation()
+	require.NoError(t, err)
+	assert.Equal(t, OrientationNormal, o)
+	for _, c := range []struct {
+		ifd   Ifd
+		tag   Tag
+		value interface{}
+	}{
+		{Ifd0, EXIF_TAG_IMAGE_WIDTH, []uint32{32}},
+		{Ifd0, EXIF_TAG_IMAGE_LENGTH, []uint32{64}},
+		{IfdExif, EXIF_TAG_PIXEL_X_DIMENSION, []uint16{32}},
+		{IfdExif, EXIF_TAG_PIXEL_Y_DIMENSION, []uint32{64}},
+	} {
+		v, err := h.GetValue(c.ifd, c.tag)
+		require.NoError(t, err)
+		assert.Equal(t, c.value, v, c.tag.Name(c.ifd))
+	}
+
+	thumb, err := data.ThumbnailImage()
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 16, 32), thumb.Bounds())
+	top, _, _, _ := thumb.At(8, 4).RGBA()
+	bottom, _, _, _ := thumb.At(8, 28).RGBA()
+	assert.True(t, top > 0xf000 && bottom < 0x1000, "top %x, bottom %x", top, bottom)
+
+	// Normalizing again changes nothing.
+	raw := len(data.Raw)
+	require.NoError(t, data.NormalizeOrientation())
+	assert.Len(t, data.Raw, raw)
+	require.NoError(t, New().NormalizeOrientation())
+
+	// Noise takes more room at ThumbnailQuality, the thumbnail no longer
+	// fits once turned.
+	noise := image.NewGray(image.Rect(0, 0, 640, 240))
+	rand.New(rand.NewSource(1)).Read(noise.Pix)
+	var small bytes.Buffer
+	require.NoError(t, jpeg.Encode(&small, noise, &jpeg.Options{Quality: 10}))
+	data = New()
+	require.NoError(t, data.Set(Ifd0, EXIF_TAG_ORIENTATION, uint16(OrientationRotate90)))
+	require.NoError(t, data.SetThumbnail(small.Bytes()))
+	entries := append([]Entry(nil), data.Entries...)
+	assert.Equal(t, ErrExifTooLarge, data.NormalizeOrientation())
+	assert.Equal(t, entries, data.Entries)
+	assert.Equal(t, small.Bytes(), data.thumbnail)
+
+	// A thumbnail that cannot be decoded cannot be turned either, it is
+	// removed and the orientation is still reset.
+	data = New()
+	require.NoError(t, data.Set(Ifd0, EXIF_TAG_ORIENTATION, uint16(OrientationRotate90)))
+	require.NoError(t, data.SetThumbnail(small.Bytes()))
+	data.thumbnail = []byte{0xff, 0xd8, 0xff, 0xd9}
+	require.NoError(t, data.NormalizeOrientation())
+	o, err = NewHelper(data).GetOrientation()
+	require.NoError(t, err)
+	assert.Equal(t, OrientationNormal, o)
+	_, err = data.Thumbnail()
+	assert.Equal(t, ErrNoThumbnail, err)
+	for _, e := range data.Entries {
+		assert.NotEqual(t, Ifd1, e.Ifd)
+	}
+}
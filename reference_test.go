@@ -0,0 +1,392 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// This file holds a minimal TIFF walker, written from the TIFF and EXIF
+// specifications without sharing code with the parsers, and the comparison
+// of its output with Data.Raw.
+
+// refEntry is an IFD entry as recorded in the file.
+type refEntry struct {
+	Ifd        Ifd
+	Tag        Tag
+	Format     EntryFormat
+	Components uint32
+	// Raw is nil when the value does not fit in the block.
+	Raw []byte
+	// Offset is the position of the 12 bytes of the entry in the TIFF data.
+	Offset int
+	// AfterBadOffset is set when a pointer or thumbnail tag before the entry
+	// in its IFD, or in the IFDs leading to it, holds an offset out of the
+	// block.
+	AfterBadOffset bool
+}
+
+// refFile is the content of an EXIF block.
+type refFile struct {
+	Order   binary.ByteOrder
+	Entries []refEntry
+	// Thumbnail is set when an IFD locates a thumbnail inside the block.
+	Thumbnail bool
+	// Problems lists the structural errors met while walking.
+	Problems []string
+}
+
+var refFormatSizes = map[EntryFormat]uint64{
+	FormatUnsignedByte:     1,
+	FormatAscii:            1,
+	FormatUnsignedShort:    2,
+	FormatUnsignedLong:     4,
+	FormatUnsignedRational: 8,
+	FormatSignedByte:       1,
+	FormatUndefined:        1,
+	FormatSignedShort:      2,
+	FormatSignedLong:       4,
+	FormatSignedRational:   8,
+	FormatFloat:            4,
+	FormatDouble:           8,
+}
+
+// refSubIfds are the pointer tags, and the IFD they point to.
+var refSubIfds = map[Tag]Ifd{
+	EXIF_TAG_EXIF_IFD_POINTER:             IfdExif,
+	EXIF_TAG_GPS_INFO_IFD_POINTER:         IfdGps,
+	EXIF_TAG_INTEROPERABILITY_IFD_POINTER: IfdInterOperability,
+}
+
+// refWalk decodes an EXIF block starting with the "Exif\x00\x00" header. IFDs
+// are visited once; pointers and the thumbnail location are not returned as
+// entries. The IFDs listed by SubIFDs or by entries of the IFD format, and the
+// ones chained after IFD1 or after them, are numbered from IfdMaxCount in the
+// order they are met, depth first.
+func refWalk(block []byte) (*refFile, error) {
+	if len(block) < 14 || string(block[:6]) != "Exif\x00\x00" {
+		return nil, fmt.Errorf("no exif header")
+	}
+	b := block[6:]
+
+	out := &refFile{}
+	switch string(b[:4]) {
+	case "II*\x00":
+		out.Order = binary.LittleEndian
+	case "MM\x00*":
+		out.Order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("bad tiff header % x", b[:4])
+	}
+
+	visited := map[uint32]Ifd{}
+	loaded := map[Ifd]bool{}
+	// read holds the offsets of the IFDs read, which other IFDs cannot
+	// reuse.
+	read := map[uint32]bool{}
+	nextIfd := IfdMaxCount
+
+	// Sub-IFDs are walked as soon as their pointer is met, and inherit
+	// badOffset from the IFD pointing to them. walk returns the offset of
+	// the next IFD.
+	var walk func(ifd Ifd, offset uint32, badOffset bool) uint32
+	// chain walks the IFDs linked from next on.
+	chain := func(next uint32) {
+		for next != 0 && uint64(next)+2 <= uint64(len(b)) && !read[next] {
+			ifd := nextIfd
+			nextIfd++
+			next = walk(ifd, next, false)
+		}
+	}
+	// subIfd walks a new IFD listed by an entry, and the IFDs chained after
+	// it.
+	var subIfd func(offset uint32)
+	walk = func(ifd Ifd, offset uint32, badOffset bool) uint32 {
+		if loaded[ifd] {
+			out.Problems = append(out.Problems, fmt.Sprintf("%s pointed to twice", ifd))
+			return 0
+		}
+		// Each IFD is loaded once, so sharing an offset cannot loop.
+		if other, ok := visited[offset]; ok {
+			out.Problems = append(out.Problems, fmt.Sprintf("%s shares offset %d with %s", ifd, offset, other))
+		} else {
+			visited[offset] = ifd
+		}
+		loaded[ifd] = true
+
+		start := uint64(offset)
+		if start+2 > uint64(len(b)) {
+			out.Problems = append(out.Problems, fmt.Sprintf("%s at %d is out of bounds", ifd, offset))
+			return 0
+		}
+		if !badOffset {
+			read[offset] = true
+		}
+		count := uint64(out.Order.Uint16(b[start:]))
+		var next uint32
+		if pos := start + 2 + 12*count; pos+4 <= uint64(len(b)) {
+			next = out.Order.Uint32(b[pos:])
+		}
+
+		var thumbOffset, thumbLength uint64
+		for i := uint64(0); i < count; i++ {
+			pos := start + 2 + 12*i
+			if pos+12 > uint64(len(b)) {
+				out.Problems = append(out.Problems, fmt.Sprintf("%s is truncated after %d entries", ifd, i))
+				break
+			}
+			e := refEntry{
+				Ifd:        ifd,
+				Tag:        Tag(out.Order.Uint16(b[pos:])),
+				Format:     EntryFormat(out.Order.Uint16(b[pos+2:])),
+				Components: out.Order.Uint32(b[pos+4:]),
+				Offset:     int(pos),
+
+				AfterBadOffset: badOffset,
+			}
+
+			_, isPointer := refSubIfds[e.Tag]
+			if isPointer || e.Tag == EXIF_TAG_JPEG_INTERCHANGE_FORMAT || e.Tag == EXIF_TAG_JPEG_INTERCHANGE_FORMAT_LENGTH {
+				if o := out.Order.Uint32(b[pos+8:]); uint64(o) >= uint64(len(b)) && !badOffset {
+					out.Problems = append(out.Problems, fmt.Sprintf("%s %s holds offset %d out of bounds", ifd, tagLabel(ifd, e.Tag), o))
+					badOffset = true
+				}
+			}
+			if isPointer {
+				walk(refSubIfds[e.Tag], out.Order.Uint32(b[pos+8:]), badOffset)
+				continue
+			}
+			if e.Tag == EXIF_TAG_JPEG_INTERCHANGE_FORMAT || e.Tag == EXIF_TAG_JPEG_INTERCHANGE_FORMAT_LENGTH {
+				if e.Tag == EXIF_TAG_JPEG_INTERCHANGE_FORMAT {
+					thumbOffset = uint64(out.Order.Uint32(b[pos+8:]))
+				} else {
+					thumbLength = uint64(out.Order.Uint32(b[pos+8:]))
+				}
+				if thumbOffset != 0 && thumbLength != 0 && thumbOffset+thumbLength <= uint64(len(b)) {
+					out.Thumbnail = true
+				}
+				continue
+			}
+
+			size := refFormatSizes[e.Format] * uint64(e.Components)
+			if e.Format == refFormatIfd {
+				size = 4 * uint64(e.Components)
+			}
+			value := pos + 8
+			if size > 4 {
+				value = uint64(out.Order.Uint32(b[pos+8:]))
+			}
+			if size != 0 && value+size <= uint64(len(b)) {
+				e.Raw = b[value : value+size]
+			}
+			if e.Format == refFormatIfd {
+				// The entry itself has no value a parser can load.
+				raw := e.Raw
+				e.Raw = nil
+				out.Entries = append(out.Entries, e)
+				if !badOffset {
+					refWalkSubIfds(raw, out.Order, b, read, subIfd)
+				}
+				continue
+			}
+			out.Entries = append(out.Entries, e)
+			if e.Tag == EXIF_TAG_SUB_IFDS && e.Format == FormatUnsignedLong && !badOffset {
+				refWalkSubIfds(e.Raw, out.Order, b, read, subIfd)
+			}
+		}
+		return next
+	}
+
+	subIfd = func(offset uint32) {
+		ifd := nextIfd
+		nextIfd++
+		chain(walk(ifd, offset, false))
+	}
+
+	// IFD1 is the next IFD after IFD0.
+	if next := walk(Ifd0, out.Order.Uint32(b[4:]), false); next != 0 {
+		chain(walk(Ifd1, next, false))
+	}
+
+	return out, nil
+}
+
+// refFormatIfd is the TIFF format of IFD offsets.
+const refFormatIfd EntryFormat = 13
+
+// refWalkSubIfds calls walk for each offset of raw, the value of a SubIFDs
+// entry, that points to an IFD not read yet.
+func refWalkSubIfds(raw []byte, order binary.ByteOrder, b []byte, read map[uint32]bool, walk func(offset uint32)) {
+	for i := 0; i+4 <= len(raw); i += 4 {
+		offset := order.Uint32(raw[i:])
+		if uint64(offset)+2 <= uint64(len(b)) && !read[offset] {
+			walk(offset)
+		}
+	}
+}
+
+// refExifBlock returns the payload of the first Exif APP1 segment of a JPEG
+// file.
+func refExifBlock(file []byte) []byte {
+	if !bytes.HasPrefix(file, []byte{0xff, 0xd8}) {
+		return nil
+	}
+	for pos := 2; pos+4 <= len(file) && file[pos] == 0xff; {
+		marker := file[pos+1]
+		if marker == 0xda || marker == 0xd9 {
+			return nil
+		}
+		end := pos + 2 + int(binary.BigEndian.Uint16(file[pos+2:]))
+		if end < pos+4 || end > len(file) {
+			return nil
+		}
+		if marker == 0xe1 && bytes.HasPrefix(file[pos+4:end], []byte("Exif\x00\x00")) {
+			return file[pos+4 : end]
+		}
+		pos = end
+	}
+	return nil
+}
+
+// rawDiff is a difference between an entry of the file and Data.Raw.
+type rawDiff struct {
+	Ifd  Ifd
+	Tag  Tag
+	Want *refEntry
+	Got  *Entry
+	// Explained is the libexif behavior that accounts for the difference,
+	// empty when nothing does.
+	Explained string
+}
+
+func (d rawDiff) String() string {
+	name := fmt.Sprintf("%s %s", d.Ifd, tagLabel(d.Ifd, d.Tag))
+	var s string
+	switch {
+	case d.Got == nil:
+		s = fmt.Sprintf("%s: in file (%s x%d), missing from Raw", name, d.Want.Format, d.Want.Components)
+	case d.Want == nil:
+		s = fmt.Sprintf("%s: not in file, in Raw as %s x%d", name, d.Got.Format, d.Got.Components)
+	default:
+		var parts []string
+		if d.Want.Format != d.Got.Format {
+			parts = append(parts, fmt.Sprintf("format %s != %s", d.Want.Format, d.Got.Format))
+		}
+		if int(d.Want.Components) != d.Got.Components {
+			parts = append(parts, fmt.Sprintf("components %d != %d", d.Want.Components, d.Got.Components))
+		}
+		if !bytes.Equal(d.Want.Raw, d.Got.Raw) {
+			parts = append(parts, fmt.Sprintf("raw % x != % x", refTruncate(d.Want.Raw), refTruncate(d.Got.Raw)))
+		}
+		s = fmt.Sprintf("%s: %s", name, strings.Join(parts, ", "))
+	}
+	if d.Explained != "" {
+		s += " (" + d.Explained + ")"
+	}
+	return s
+}
+
+// refLoadable reports whether e has a value a parser can load.
+func refLoadable(e *refEntry) bool {
+	return e.Raw != nil && !e.AfterBadOffset
+}
+
+func refTruncate(b []byte) []byte {
+	if len(b) > 16 {
+		return b[:16]
+	}
+	return b
+}
+
+// diffRaw compares the entries of the file with raw. Raw holds one entry per
+// tag, so only the first occurrence of a tag in an IFD that can be loaded is
+// compared, or the first occurrence when none can.
+func diffRaw(file *refFile, raw map[IfdTag]Entry) []rawDiff {
+	var diffs []rawDiff
+	first := map[IfdTag]*refEntry{}
+	var keys []IfdTag
+
+	for i := range file.Entries {
+		e := &file.Entries[i]
+		key := NewIfdTag(uint16(e.Ifd), uint16(e.Tag))
+		prev, ok := first[key]
+		if !ok {
+			keys = append(keys, key)
+		}
+		if !ok || (!refLoadable(prev) && refLoadable(e)) {
+			first[key] = e
+		}
+	}
+
+	for _, key := range keys {
+		want := first[key]
+		got, ok := raw[key]
+		switch {
+		case !ok:
+			diffs = append(diffs, rawDiff{Ifd: want.Ifd, Tag: want.Tag, Want: want})
+		case want.Format != got.Format || int(want.Components) != got.Components || !bytes.Equal(want.Raw, got.Raw):
+			diffs = append(diffs, rawDiff{Ifd: want.Ifd, Tag: want.Tag, Want: want, Got: &got})
+		}
+	}
+
+	for key, got := range raw {
+		if first[key] == nil {
+			got := got
+			diffs = append(diffs, rawDiff{Ifd: got.Ifd, Tag: got.Tag, Got: &got})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Ifd != diffs[j].Ifd {
+			return diffs[i].Ifd < diffs[j].Ifd
+		}
+		return diffs[i].Tag < diffs[j].Tag
+	})
+	for i := range diffs {
+		diffs[i].Explained = explainDiff(file, diffs[i])
+	}
+	return diffs
+}
+
+// explainDiff returns the documented behavior of the parser that accounts
+// for d, or an empty string.
+func explainDiff(file *refFile, d rawDiff) string {
+	if d.Got == nil {
+		switch {
+		case d.Want.AfterBadOffset:
+			return "rest of the ifd dropped after an offset out of bounds"
+		case refFormatSizes[d.Want.Format]*uint64(d.Want.Components) == 0:
+			return "entry without value dropped"
+		case d.Want.Raw == nil:
+			return "value out of bounds dropped"
+		case d.Ifd >= IfdMaxCount:
+			// The IFDs libexif does not know are loaded as they are.
+		case d.Tag.Name(d.Ifd) == "":
+			return "unknown tag ignored"
+		case libexifFixes && d.Ifd == Ifd1 && !file.Thumbnail:
+			return "ifd1 without thumbnail removed"
+		case libexifFixes && !TagAllowed(d.Ifd, d.Tag):
+			return "entry not recorded in its ifd removed"
+		}
+		return ""
+	}
+
+	if !libexifFixes || d.Ifd >= IfdMaxCount {
+		return ""
+	}
+	switch {
+	case d.Want == nil && d.Tag.Name(d.Ifd) != "":
+		return "mandatory entry added"
+	case d.Want != nil && d.Want.Raw == nil:
+		return "invalid entry replaced by its default"
+	case d.Want != nil && d.Want.Format != d.Got.Format:
+		for _, f := range ExpectedFormats(d.Ifd, d.Tag) {
+			if f == d.Got.Format {
+				return "converted to the specified format"
+			}
+		}
+	}
+	return ""
+}
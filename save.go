@@ -0,0 +1,218 @@
+package exif
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Error messages.
+var (
+	ErrNotJpeg      = errors.New(`not a jpeg file`)
+	ErrExifTooLarge = errors.New(`exif data does not fit in a jpeg segment`)
+	ErrInvalidIfd   = errors.New(`invalid ifd`)
+	ErrSaveExifData = errors.New(`cannot serialize exif data`)
+	ErrNoMemory     = errors.New(`cannot allocate exif data`)
+)
+
+const (
+	jpegMarkerSOI  = 0xd8
+	jpegMarkerEOI  = 0xd9
+	jpegMarkerSOS  = 0xda
+	jpegMarkerAPP0 = 0xe0
+	jpegMarkerAPP1 = 0xe1
+
+	// jpegMaxSegment is the largest payload a segment length field can hold.
+	jpegMaxSegment = 0xffff - 2
+)
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// Save copies the JPEG image read from src to w, replacing its EXIF segment
+// with one built from the current Raw entries. The image data is copied
+// untouched. The IFDs libexif does not know are left out, see IfdInfo.
+func (d *Data) Save(w io.Writer, src io.Reader) error {
+	payload, err := d.marshal()
+	if err != nil {
+		return err
+	}
+
+	return spliceExif(w, src, payload)
+}
+
+// SaveFile rewrites the EXIF segment of the JPEG file at the given path.
+func (d *Data) SaveFile(file string) error {
+	src, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	stat, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	out := bufio.NewWriter(tmp)
+	if err = d.Save(out, bufio.NewReader(src)); err == nil {
+		err = out.Flush()
+	}
+	if err == nil {
+		err = tmp.Chmod(stat.Mode())
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
+
+// isLayoutTag reports whether tag describes the position of other data in
+// the file rather than a value. SubIFDs are not written, so neither is the
+// entry listing them.
+func isLayoutTag(tag Tag) bool {
+	switch tag {
+	case EXIF_TAG_SUB_IFDS,
+		EXIF_TAG_EXIF_IFD_POINTER,
+		EXIF_TAG_GPS_INFO_IFD_POINTER,
+		EXIF_TAG_INTEROPERABILITY_IFD_POINTER,
+		EXIF_TAG_JPEG_INTERCHANGE_FORMAT,
+		EXIF_TAG_JPEG_INTERCHANGE_FORMAT_LENGTH:
+		return true
+	}
+	return false
+}
+
+type jpegSegment struct {
+	marker byte
+	data   []byte
+}
+
+func (s *jpegSegment) isExif() bool {
+	return s.marker == jpegMarkerAPP1 && bytes.HasPrefix(s.data, exifHeader)
+}
+
+// spliceExif copies a JPEG stream from src to w, putting an APP1 segment
+// holding payload in place of the existing EXIF segment. When there is none,
+// the new segment goes right after the leading APP0 (JFIF) segments.
+func spliceExif(w io.Writer, src io.Reader, payload []byte) error {
+	if len(payload) > jpegMaxSegment {
+		return ErrExifTooLarge
+	}
+
+	var soi [2]byte
+	if _, err := io.ReadFull(src, soi[:]); err != nil {
+		return ErrNotJpeg
+	}
+	if soi[0] != 0xff || soi[1] != jpegMarkerSOI {
+		return ErrNotJpeg
+	}
+
+	// Header segments are small, keep them until the start of the image data
+	// so the EXIF segment can be replaced where it is.
+	var segments []jpegSegment
+	var last byte
+	for {
+		marker, err := readMarker(src)
+		if err != nil {
+			return err
+		}
+		if marker == jpegMarkerSOS || marker == jpegMarkerEOI {
+			last = marker
+			break
+		}
+
+		var size [2]byte
+		if _, err := io.ReadFull(src, size[:]); err != nil {
+			return err
+		}
+		length := int(binary.BigEndian.Uint16(size[:]))
+		if length < 2 {
+			return ErrNotJpeg
+		}
+		data := make([]byte, length-2)
+		if _, err := io.ReadFull(src, data); err != nil {
+			return err
+		}
+		segments = append(segments, jpegSegment{marker: marker, data: data})
+	}
+
+	exif := jpegSegment{marker: jpegMarkerAPP1, data: payload}
+	pos := -1
+	for i := range segments {
+		if segments[i].isExif() {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		pos = 0
+		for pos < len(segments) && segments[pos].marker == jpegMarkerAPP0 {
+			pos++
+		}
+		segments = append(segments[:pos], append([]jpegSegment{exif}, segments[pos:]...)...)
+	} else {
+		segments[pos] = exif
+	}
+
+	if _, err := w.Write(soi[:]); err != nil {
+		return err
+	}
+	for i := range segments {
+		// The old EXIF segment may have been repeated.
+		if i != pos && segments[i].isExif() {
+			continue
+		}
+		if err := writeSegment(w, &segments[i]); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.Write([]byte{0xff, last}); err != nil {
+		return err
+	}
+	_, err := io.Copy(w, src)
+	return err
+}
+
+func readMarker(r io.Reader) (byte, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	if b[0] != 0xff {
+		return 0, ErrNotJpeg
+	}
+	// Any number of 0xff fill bytes may precede a marker.
+	for b[0] == 0xff {
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return 0, err
+		}
+	}
+	return b[0], nil
+}
+
+func writeSegment(w io.Writer, s *jpegSegment) error {
+	var head [4]byte
+	head[0] = 0xff
+	head[1] = s.marker
+	binary.BigEndian.PutUint16(head[2:], uint16(len(s.data)+2))
+	if _, err := w.Write(head[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(s.data)
+	return err
+}
@@ -0,0 +1,149 @@
+//go:build cgo
+
+package exif
+
+/*
+#include <stdlib.h>
+#include <libexif/exif-data.h>
+#include <libexif/exif-entry.h>
+#include <libexif/exif-content.h>
+#include <libexif/exif-byte-order.h>
+*/
+import "C"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"unsafe"
+)
+
+// marshal serializes the Raw entries into an APP1 payload, including the
+// "Exif\0\0" header.
+func (d *Data) marshal() ([]byte, error) {
+	ed, err := d.buildExifData()
+	if err != nil {
+		return nil, err
+	}
+	defer C.exif_data_unref(ed)
+
+	var buf *C.uchar
+	var size C.uint
+
+	C.exif_data_save_data(ed, &buf, &size)
+	if buf == nil {
+		return nil, ErrSaveExifData
+	}
+	defer C.free(unsafe.Pointer(buf))
+
+	if size == 0 {
+		return nil, ErrSaveExifData
+	}
+
+	return C.GoBytes(unsafe.Pointer(buf), C.int(size)), nil
+}
+
+// buildExifData creates a new libexif ExifData holding a copy of every Raw
+// entry. The caller must release it with exif_data_unref.
+func (d *Data) buildExifData() (*C.ExifData, error) {
+	ed, err := d.newSaveData()
+	if err != nil {
+		return nil, err
+	}
+
+	setByteOrder(ed, d.Order)
+
+	for _, entry := range d.Raw {
+		if isLayoutTag(entry.Tag) {
+			// libexif writes these itself when saving.
+			continue
+		}
+		if entry.Ifd >= IfdMaxCount && d.ifdInfo(entry.Ifd) != nil {
+			// See IfdInfo.
+			continue
+		}
+		if entry.Ifd >= IfdMaxCount {
+			C.exif_data_unref(ed)
+			return nil, fmt.Errorf("%w: %d", ErrInvalidIfd, entry.Ifd)
+		}
+		if _, err := addExifEntry(ed.ifd[entry.Ifd], &entry); err != nil {
+			C.exif_data_unref(ed)
+			return nil, err
+		}
+	}
+
+	if len(d.thumbnail) != 0 {
+		ed.data = (*C.uchar)(C.CBytes(d.thumbnail))
+		ed.size = C.uint(len(d.thumbnail))
+	}
+
+	return ed, nil
+}
+
+// newSaveData creates an empty ExifData to fill with the Raw entries. Unless
+// Options.DontChangeMakerNote is set, it holds the maker note libexif
+// interpreted from the data read, which libexif rewrites when saving.
+func (d *Data) newSaveData() (*C.ExifData, error) {
+	ed, err := newExifData(d.Options)
+	if err != nil || d.Options.DontChangeMakerNote || len(d.rawExif) == 0 {
+		return ed, err
+	}
+	note, ok := d.Raw[NewIfdTag(uint16(IfdExif), uint16(EXIF_TAG_MAKER_NOTE))]
+	if !ok {
+		return ed, nil
+	}
+
+	C.exif_data_load_data(ed, (*C.uchar)(unsafe.Pointer(&d.rawExif[0])), C.uint(len(d.rawExif)))
+	loaded := C.exif_content_get_entry(ed.ifd[IfdExif], C.EXIF_TAG_MAKER_NOTE)
+	if C.exif_data_get_mnote_data(ed) == nil || loaded == nil ||
+		!bytes.Equal(note.Raw, C.GoBytes(unsafe.Pointer(loaded.data), C.int(loaded.size))) {
+		// Not understood by libexif or changed since, it is written as is.
+		C.exif_data_unref(ed)
+		return newExifData(d.Options)
+	}
+
+	// Only the interpreted maker note is kept, the entries come from Raw.
+	for i := range ed.ifd {
+		for ed.ifd[i].count > 0 {
+			C.exif_content_remove_entry(ed.ifd[i], *ed.ifd[i].entries)
+		}
+	}
+	if ed.data != nil {
+		C.free(unsafe.Pointer(ed.data))
+		ed.data = nil
+		ed.size = 0
+	}
+	return ed, nil
+}
+
+// setByteOrder sets the byte order of ed. It must be called before adding
+// entries, as libexif converts the existing ones when it changes.
+func setByteOrder(ed *C.ExifData, order binary.ByteOrder) {
+	if order == binary.LittleEndian {
+		C.exif_data_set_byte_order(ed, C.EXIF_BYTE_ORDER_INTEL)
+	} else {
+		C.exif_data_set_byte_order(ed, C.EXIF_BYTE_ORDER_MOTOROLA)
+	}
+}
+
+// addExifEntry adds a copy of entry to content and returns it. The copy is
+// owned by content.
+func addExifEntry(content *C.ExifContent, entry *Entry) (*C.ExifEntry, error) {
+	ce := C.exif_entry_new()
+	if ce == nil {
+		return nil, ErrNoMemory
+	}
+	defer C.exif_entry_unref(ce)
+
+	ce.tag = C.ExifTag(entry.Tag)
+	ce.format = C.ExifFormat(entry.Format)
+	ce.components = C.ulong(entry.Components)
+	if len(entry.Raw) != 0 {
+		// The default ExifMem releases entry data with free().
+		ce.data = (*C.uchar)(C.CBytes(entry.Raw))
+		ce.size = C.uint(len(entry.Raw))
+	}
+
+	C.exif_content_add_entry(content, ce)
+	return ce, nil
+}
@@ -0,0 +1,178 @@
+//go:build !cgo
+
+package exif
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// marshal serializes the Raw entries into an APP1 payload, including the
+// "Exif\0\0" header.
+func (d *Data) marshal() ([]byte, error) {
+	var ifds [IfdMaxCount][]Entry
+	for _, entry := range d.Raw {
+		if isLayoutTag(entry.Tag) {
+			// Written below from the actual layout.
+			continue
+		}
+		if entry.Ifd >= IfdMaxCount && d.ifdInfo(entry.Ifd) != nil {
+			// See IfdInfo.
+			continue
+		}
+		if entry.Ifd >= IfdMaxCount {
+			return nil, fmt.Errorf("%w: %d", ErrInvalidIfd, entry.Ifd)
+		}
+		ifds[entry.Ifd] = append(ifds[entry.Ifd], entry)
+	}
+
+	w := &tiffWriter{order: binary.BigEndian}
+	if d.Order == binary.LittleEndian {
+		w.order = binary.LittleEndian
+	}
+	w.writeHeader()
+
+	hasExif := len(ifds[IfdExif]) != 0 || len(ifds[IfdInterOperability]) != 0
+	hasIfd1 := len(ifds[Ifd1]) != 0 || len(d.thumbnail) != 0
+
+	if hasExif {
+		ifds[Ifd0] = append(ifds[Ifd0], w.pointer(Ifd0, EXIF_TAG_EXIF_IFD_POINTER))
+	}
+	if len(ifds[IfdGps]) != 0 {
+		ifds[Ifd0] = append(ifds[Ifd0], w.pointer(Ifd0, EXIF_TAG_GPS_INFO_IFD_POINTER))
+	}
+	if len(ifds[IfdInterOperability]) != 0 {
+		ifds[IfdExif] = append(ifds[IfdExif], w.pointer(IfdExif, EXIF_TAG_INTEROPERABILITY_IFD_POINTER))
+	}
+	if len(d.thumbnail) != 0 {
+		ifds[Ifd1] = append(ifds[Ifd1],
+			w.pointer(Ifd1, EXIF_TAG_JPEG_INTERCHANGE_FORMAT),
+			w.pointer(Ifd1, EXIF_TAG_JPEG_INTERCHANGE_FORMAT_LENGTH))
+	}
+
+	ifd0 := w.writeIfd(ifds[Ifd0])
+	if hasExif {
+		exif := w.writeIfd(ifds[IfdExif])
+		w.patch(ifd0.fields[EXIF_TAG_EXIF_IFD_POINTER], exif.start)
+		if len(ifds[IfdInterOperability]) != 0 {
+			interop := w.writeIfd(ifds[IfdInterOperability])
+			w.patch(exif.fields[EXIF_TAG_INTEROPERABILITY_IFD_POINTER], interop.start)
+		}
+	}
+	if len(ifds[IfdGps]) != 0 {
+		gps := w.writeIfd(ifds[IfdGps])
+		w.patch(ifd0.fields[EXIF_TAG_GPS_INFO_IFD_POINTER], gps.start)
+	}
+	if hasIfd1 {
+		ifd1 := w.writeIfd(ifds[Ifd1])
+		w.patch(ifd0.next, ifd1.start)
+		if len(d.thumbnail) != 0 {
+			w.patch(ifd1.fields[EXIF_TAG_JPEG_INTERCHANGE_FORMAT], len(w.buf))
+			w.patch(ifd1.fields[EXIF_TAG_JPEG_INTERCHANGE_FORMAT_LENGTH], len(d.thumbnail))
+			w.buf = append(w.buf, d.thumbnail...)
+		}
+	}
+
+	if len(w.buf)+len(exifHeader) > jpegMaxSegment {
+		return nil, ErrExifTooLarge
+	}
+	return append(append([]byte(nil), exifHeader...), w.buf...), nil
+}
+
+// tiffWriter lays out IFDs one after the other, each followed by the values
+// that do not fit in its entries.
+type tiffWriter struct {
+	buf   []byte
+	order binary.ByteOrder
+}
+
+// writtenIfd records where the offsets of an IFD written by writeIfd are,
+// so they can be patched once known.
+type writtenIfd struct {
+	start  int
+	next   int
+	fields map[Tag]int
+}
+
+func (w *tiffWriter) writeHeader() {
+	if w.order == binary.LittleEndian {
+		w.buf = append(w.buf, 'I', 'I')
+	} else {
+		w.buf = append(w.buf, 'M', 'M')
+	}
+	w.uint16(0x002a)
+	w.uint32(8)
+}
+
+// pointer returns a LONG placeholder entry for an offset patched later.
+func (w *tiffWriter) pointer(ifd Ifd, tag Tag) Entry {
+	return Entry{
+		Ifd:        ifd,
+		Tag:        tag,
+		Format:     FormatUnsignedLong,
+		Components: 1,
+		Raw:        make([]byte, 4),
+		order:      w.order,
+	}
+}
+
+func (w *tiffWriter) writeIfd(entries []Entry) writtenIfd {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Tag < entries[j].Tag
+	})
+
+	// Offsets are word aligned.
+	if len(w.buf)%2 != 0 {
+		w.buf = append(w.buf, 0)
+	}
+	out := writtenIfd{
+		start:  len(w.buf),
+		fields: make(map[Tag]int),
+	}
+
+	w.uint16(uint16(len(entries)))
+	dataOffset := len(w.buf) + 12*len(entries) + 4
+
+	var values []byte
+	for _, e := range entries {
+		w.uint16(uint16(e.Tag))
+		w.uint16(uint16(e.Format))
+		w.uint32(uint32(e.Components))
+		out.fields[e.Tag] = len(w.buf)
+
+		if len(e.Raw) <= 4 {
+			var field [4]byte
+			copy(field[:], e.Raw)
+			w.buf = append(w.buf, field[:]...)
+			continue
+		}
+		w.uint32(uint32(dataOffset + len(values)))
+		values = append(values, e.Raw...)
+		if len(values)%2 != 0 {
+			values = append(values, 0)
+		}
+	}
+
+	out.next = len(w.buf)
+	w.uint32(0)
+	w.buf = append(w.buf, values...)
+	return out
+}
+
+func (w *tiffWriter) uint16(v uint16) {
+	var b [2]byte
+	w.order.PutUint16(b[:], v)
+	w.buf = append(w.buf, b[:]...)
+}
+
+func (w *tiffWriter) uint32(v uint32) {
+	var b [4]byte
+	w.order.PutUint32(b[:], v)
+	w.buf = append(w.buf, b[:]...)
+}
+
+// patch writes a LONG offset at pos.
+func (w *tiffWriter) patch(pos, value int) {
+	w.order.PutUint32(w.buf[pos:], uint32(value))
+}
@@ -0,0 +1,94 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// exifScanner looks for the EXIF segment of a JPEG stream, keeping only the
+// bytes of the segment being read.
+type exifScanner struct {
+	buf  []byte
+	skip int
+	// pos is the position of buf in the stream.
+	pos int
+
+	started bool
+	done    bool
+	exif    []byte
+	// exifOffset is the position of exif in the stream.
+	exifOffset int
+}
+
+// write feeds p to the scanner, and reports whether it wants more data.
+func (s *exifScanner) write(p []byte) bool {
+	if s.done {
+		return false
+	}
+
+	n := s.skip
+	if n > len(p) {
+		n = len(p)
+	}
+	s.skip -= n
+	s.pos += n
+	s.buf = append(s.buf, p[n:]...)
+
+	for !s.done {
+		if !s.started {
+			if len(s.buf) < 2 {
+				return true
+			}
+			if s.buf[0] != 0xff || s.buf[1] != jpegMarkerSOI {
+				s.done = true
+				break
+			}
+			s.consume(2)
+			s.started = true
+			continue
+		}
+
+		if len(s.buf) >= 2 && s.buf[0] == 0xff && s.buf[1] == 0xff {
+			// Fill byte.
+			s.consume(1)
+			continue
+		}
+		if len(s.buf) < 4 {
+			return true
+		}
+
+		marker := s.buf[1]
+		size := int(binary.BigEndian.Uint16(s.buf[2:])) + 2
+		if s.buf[0] != 0xff || marker == jpegMarkerSOS || marker == jpegMarkerEOI || size < 4 {
+			s.done = true
+			break
+		}
+
+		if marker == jpegMarkerAPP1 {
+			if len(s.buf) < size {
+				return true
+			}
+			if payload := s.buf[4:size]; bytes.HasPrefix(payload, exifHeader) {
+				s.exif = append([]byte(nil), payload...)
+				s.exifOffset = s.pos + 4
+				s.done = true
+				break
+			}
+		}
+
+		if len(s.buf) < size {
+			s.skip = size - len(s.buf)
+			s.consume(len(s.buf))
+			return true
+		}
+		s.consume(size)
+	}
+
+	s.buf = nil
+	return false
+}
+
+func (s *exifScanner) consume(n int) {
+	s.buf = s.buf[n:]
+	s.pos += n
+}
@@ -0,0 +1,27 @@
+package exif
+
+import "fmt"
+
+// TagInfo describes a tag known to libexif.
+type TagInfo struct {
+	Tag  Tag
+	Name string
+	// Ifds lists the IFDs the tag is defined for under this name.
+	Ifds []Ifd
+}
+
+func (i Ifd) String() string {
+	switch i {
+	case Ifd0:
+		return "IFD0"
+	case Ifd1:
+		return "IFD1"
+	case IfdExif:
+		return "EXIF"
+	case IfdGps:
+		return "GPS"
+	case IfdInterOperability:
+		return "Interoperability"
+	}
+	return fmt.Sprintf("IFD(%d)", uint16(i))
+}
@@ -0,0 +1,116 @@
+//go:build cgo
+
+package exif
+
+/*
+#include <stdlib.h>
+#include <libexif/exif-tag.h>
+#include <libexif/exif-format.h>
+
+// tag_allowed_in_ifd returns 1 when the specification allows tag to be
+// recorded in ifd for at least one data type. Tags unknown to libexif are
+// always allowed.
+static int tag_allowed_in_ifd(ExifTag tag, ExifIfd ifd) {
+	int t;
+
+	if (!exif_tag_get_name(tag))
+		return 1;
+
+	for (t = 0; t < EXIF_DATA_TYPE_COUNT; t++) {
+		if (exif_tag_get_support_level_in_ifd(tag, ifd, (ExifDataType)t) !=
+		    EXIF_SUPPORT_LEVEL_NOT_RECORDED)
+			return 1;
+	}
+	return 0;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Name returns the name of the tag in ifd, such as "FNumber", or an empty
+// string when it is not known.
+func (t Tag) Name(ifd Ifd) string {
+	ifd = ifd.tableIfd()
+	return goString(C.exif_tag_get_name_in_ifd(C.ExifTag(t), C.ExifIfd(ifd)))
+}
+
+// Title returns the localized title of the tag in ifd, such as "F-Number".
+func (t Tag) Title(ifd Ifd) string {
+	ifd = ifd.tableIfd()
+	return goString(C.exif_tag_get_title_in_ifd(C.ExifTag(t), C.ExifIfd(ifd)))
+}
+
+// Description returns the localized description of the tag in ifd.
+func (t Tag) Description(ifd Ifd) string {
+	ifd = ifd.tableIfd()
+	return goString(C.exif_tag_get_description_in_ifd(C.ExifTag(t), C.ExifIfd(ifd)))
+}
+
+// TagFromName returns the tag with the given name, as returned by Tag.Name.
+func TagFromName(name string) (Tag, bool) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	tag := Tag(C.exif_tag_from_name(cname))
+	// exif_tag_from_name returns 0 for unknown names, which is also the
+	// value of GPSVersionID.
+	for ifd := Ifd0; ifd < IfdMaxCount; ifd++ {
+		if tag.Name(ifd) == name {
+			return tag, true
+		}
+	}
+	return 0, false
+}
+
+// KnownTags lists every tag of the libexif tag table.
+func KnownTags() []TagInfo {
+	count := int(C.exif_tag_table_count())
+	out := make([]TagInfo, 0, count)
+
+	for i := 0; i < count; i++ {
+		name := goString(C.exif_tag_table_get_name(C.uint(i)))
+		if name == "" {
+			continue
+		}
+		info := TagInfo{
+			Tag:  Tag(C.exif_tag_table_get_tag(C.uint(i))),
+			Name: name,
+		}
+		for ifd := Ifd0; ifd < IfdMaxCount; ifd++ {
+			if info.Tag.Name(ifd) == name {
+				info.Ifds = append(info.Ifds, ifd)
+			}
+		}
+		out = append(out, info)
+	}
+
+	return out
+}
+
+// TagAllowed reports whether tag may be recorded in ifd. It is false for the
+// IFDs numbered from IfdMaxCount, whose entries are read-only.
+func TagAllowed(ifd Ifd, tag Tag) bool {
+	if ifd >= IfdMaxCount {
+		return false
+	}
+	return C.tag_allowed_in_ifd(C.ExifTag(tag), C.ExifIfd(ifd)) != 0
+}
+
+// String returns the libexif name of the format, such as "Rational".
+func (f EntryFormat) String() string {
+	if name := goString(C.exif_format_get_name(C.ExifFormat(f))); name != "" {
+		return name
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+func goString(s *C.char) string {
+	if s == nil {
+		return ""
+	}
+	return C.GoString(s)
+}
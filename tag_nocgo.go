@@ -0,0 +1,127 @@
+//go:build !cgo
+
+package exif
+
+import "fmt"
+
+//go:generate go run gen_tags.go
+
+// tagTableEntry is a row of the libexif tag table. ifds has bit n set when
+// the tag is recorded in Ifd(n) under this name.
+type tagTableEntry struct {
+	tag         Tag
+	name        string
+	title       string
+	description string
+	ifds        uint8
+}
+
+// lookupTag returns the table row describing tag in ifd.
+func lookupTag(t Tag, ifd Ifd) *tagTableEntry {
+	ifd = ifd.tableIfd()
+	for i := range tagTable {
+		if tagTable[i].tag == t && tagTable[i].ifds&(1<<ifd) != 0 {
+			return &tagTable[i]
+		}
+	}
+	return nil
+}
+
+// Name returns the name of the tag in ifd, such as "FNumber", or an empty
+// string when it is not known.
+func (t Tag) Name(ifd Ifd) string {
+	if e := lookupTag(t, ifd); e != nil {
+		return e.name
+	}
+	return ""
+}
+
+// Title returns the title of the tag in ifd, such as "F-Number".
+func (t Tag) Title(ifd Ifd) string {
+	if e := lookupTag(t, ifd); e != nil {
+		return e.title
+	}
+	return ""
+}
+
+// Description returns the description of the tag in ifd.
+func (t Tag) Description(ifd Ifd) string {
+	if e := lookupTag(t, ifd); e != nil {
+		return e.description
+	}
+	return ""
+}
+
+// TagFromName returns the tag with the given name, as returned by Tag.Name.
+func TagFromName(name string) (Tag, bool) {
+	for _, e := range tagTable {
+		if e.name == name && e.ifds != 0 {
+			return e.tag, true
+		}
+	}
+	return 0, false
+}
+
+// KnownTags lists every tag of the libexif tag table.
+func KnownTags() []TagInfo {
+	out := make([]TagInfo, 0, len(tagTable))
+
+	for _, e := range tagTable {
+		info := TagInfo{
+			Tag:  e.tag,
+			Name: e.name,
+		}
+		for ifd := Ifd0; ifd < IfdMaxCount; ifd++ {
+			if e.ifds&(1<<ifd) != 0 {
+				info.Ifds = append(info.Ifds, ifd)
+			}
+		}
+		out = append(out, info)
+	}
+
+	return out
+}
+
+// TagAllowed reports whether tag may be recorded in ifd. It is false for the
+// IFDs numbered from IfdMaxCount, whose entries are read-only.
+func TagAllowed(ifd Ifd, tag Tag) bool {
+	if ifd >= IfdMaxCount {
+		return false
+	}
+
+	known := false
+	for _, e := range tagTable {
+		if e.tag != tag {
+			continue
+		}
+		if e.ifds&(1<<ifd) != 0 {
+			return true
+		}
+		known = known || e.ifds != 0
+	}
+	// Tags unknown to libexif are always allowed.
+	return !known
+}
+
+var formatNames = map[EntryFormat]string{
+	FormatUnsignedByte:     "Byte",
+	FormatAscii:            "ASCII",
+	FormatUnsignedShort:    "Short",
+	FormatUnsignedLong:     "Long",
+	FormatUnsignedRational: "Rational",
+	FormatSignedByte:       "SByte",
+	FormatUndefined:        "Undefined",
+	FormatSignedShort:      "SShort",
+	FormatSignedLong:       "SLong",
+	FormatSignedRational:   "SRational",
+	FormatFloat:            "Float",
+	FormatDouble:           "Double",
+}
+
+// String returns the libexif name of the format, such as "Rational".
+func (f EntryFormat) String() string {
+	if name, ok := formatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
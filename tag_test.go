@@ -0,0 +1,49 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagNames(t *testing.T) {
+	assert.Equal(t, "FNumber", EXIF_TAG_FNUMBER.Name(IfdExif))
+	assert.Equal(t, "F-Number", EXIF_TAG_FNUMBER.Title(IfdExif))
+	assert.NotEmpty(t, EXIF_TAG_FNUMBER.Description(IfdExif))
+	assert.Equal(t, "GPSLatitudeRef", EXIF_TAG_GPS_LATITUDE_REF.Name(IfdGps))
+	assert.Equal(t, "InteroperabilityIndex", EXIF_TAG_INTEROPERABILITY_INDEX.Name(IfdInterOperability))
+	assert.Equal(t, "", Tag(0xfeed).Name(IfdExif))
+
+	tag, ok := TagFromName("FNumber")
+	require.True(t, ok)
+	assert.Equal(t, EXIF_TAG_FNUMBER, tag)
+	tag, ok = TagFromName("GPSVersionID")
+	require.True(t, ok)
+	assert.Equal(t, EXIF_TAG_GPS_VERSION_ID, tag)
+	_, ok = TagFromName("NoSuchTag")
+	assert.False(t, ok)
+
+	assert.Equal(t, "GPS", IfdGps.String())
+	assert.Equal(t, "Rational", FormatUnsignedRational.String())
+
+	key := NewIfdTag(uint16(IfdExif), uint16(EXIF_TAG_FNUMBER))
+	assert.Equal(t, "<ifd: EXIF, tag: FNumber>", key.String())
+	key = NewIfdTag(uint16(IfdExif), 0xfeed)
+	assert.Equal(t, "<ifd: EXIF, tag: 0xfeed>", key.String())
+}
+
+func TestKnownTags(t *testing.T) {
+	tags := KnownTags()
+	require.NotEmpty(t, tags)
+
+	var found bool
+	for _, info := range tags {
+		if info.Name == "GPSLatitude" {
+			found = true
+			assert.Equal(t, EXIF_TAG_GPS_LATITUDE, info.Tag)
+			assert.Equal(t, []Ifd{IfdGps}, info.Ifds)
+		}
+	}
+	assert.True(t, found)
+}
@@ -0,0 +1,300 @@
+package exif
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+)
+
+// Error messages.
+var (
+	ErrNoThumbnail          = errors.New(`no thumbnail found`)
+	ErrUnsupportedThumbnail = errors.New(`unsupported thumbnail format`)
+)
+
+// TIFF compression and photometric interpretation values used by
+// thumbnails.
+const (
+	compressionNone = 1
+	photometricRGB  = 2
+)
+
+// maxThumbnailSide is the largest width or height of an uncompressed
+// thumbnail, whose strips fit in a JPEG segment anyway.
+const maxThumbnailSide = 0xffff
+
+// Thumbnail returns the thumbnail embedded in IFD1. It is either a JPEG
+// image, or the uncompressed pixel data of the strips when IFD1 describes an
+// uncompressed image.
+func (d *Data) Thumbnail() ([]byte, error) {
+	if len(d.thumbnail) != 0 {
+		return d.thumbnail, nil
+	}
+
+	return d.stripThumbnail()
+}
+
+// ThumbnailImage decodes the thumbnail embedded in IFD1. Uncompressed
+// thumbnails must be 8 bit RGB.
+func (d *Data) ThumbnailImage() (image.Image, error) {
+	if len(d.thumbnail) != 0 {
+		return jpeg.Decode(bytes.NewReader(d.thumbnail))
+	}
+
+	pix, err := d.stripThumbnail()
+	if err != nil {
+		return nil, err
+	}
+
+	h := NewHelper(d)
+	if h.getUint(Ifd1, EXIF_TAG_PHOTOMETRIC_INTERPRETATION, photometricRGB) != photometricRGB ||
+		h.getUint(Ifd1, EXIF_TAG_SAMPLES_PER_PIXEL, 1) != 3 ||
+		h.getUint(Ifd1, EXIF_TAG_PLANAR_CONFIGURATION, 1) != 1 {
+		return nil, ErrUnsupportedThumbnail
+	}
+	if v, err := h.GetValue(Ifd1, EXIF_TAG_BITS_PER_SAMPLE); err == nil {
+		bits, ok := v.([]uint16)
+		if !ok {
+			return nil, ErrUnsupportedThumbnail
+		}
+		for _, b := range bits {
+			if b != 8 {
+				return nil, ErrUnsupportedThumbnail
+			}
+		}
+	}
+
+	// The dimensions are bounded, so that their product cannot overflow.
+	w := uint64(h.getUint(Ifd1, EXIF_TAG_IMAGE_WIDTH, 0))
+	l := uint64(h.getUint(Ifd1, EXIF_TAG_IMAGE_LENGTH, 0))
+	if w == 0 || l == 0 || w > maxThumbnailSide || l > maxThumbnailSide || uint64(len(pix)) < w*l*3 {
+		return nil, ErrUnsupportedThumbnail
+	}
+	width, height := int(w), int(l)
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			p := pix[(y*width+x)*3:]
+			img.SetRGBA(x, y, color.RGBA{R: p[0], G: p[1], B: p[2], A: 0xff})
+		}
+	}
+	return img, nil
+}
+
+// stripThumbnail returns the concatenated strips of an uncompressed IFD1
+// image.
+func (d *Data) stripThumbnail() ([]byte, error) {
+	h := NewHelper(d)
+	if h.getUint(Ifd1, EXIF_TAG_COMPRESSION, 0) != compressionNone {
+		return nil, ErrNoThumbnail
+	}
+
+	offsets, err := h.getUints(Ifd1, EXIF_TAG_STRIP_OFFSETS)
+	if err != nil {
+		return nil, ErrNoThumbnail
+	}
+	counts, err := h.getUints(Ifd1, EXIF_TAG_STRIP_BYTE_COUNTS)
+	if err != nil {
+		return nil, ErrNoThumbnail
+	}
+	if len(offsets) != len(counts) {
+		return nil, h.entryError(Ifd1, EXIF_TAG_STRIP_BYTE_COUNTS, 0, ErrLengthNotMatch)
+	}
+
+	tiff := tiffData(d.rawExif)
+	var out []byte
+	for i := range offsets {
+		start, end := uint64(offsets[i]), uint64(offsets[i])+uint64(counts[i])
+		if end > uint64(len(tiff)) {
+			return nil, ErrValueTooSmall
+		}
+		out = append(out, tiff[start:end]...)
+	}
+	if len(out) == 0 {
+		return nil, ErrNoThumbnail
+	}
+	return out, nil
+}
+
+// tiffData strips the "Exif\0\0" header from raw EXIF data, leaving the TIFF
+// structure all offsets are relative to.
+func tiffData(raw []byte) []byte {
+	if bytes.HasPrefix(raw, exifHeader) {
+		return raw[len(exifHeader):]
+	}
+	return raw
+}
+
+// getUints reads a SHORT or LONG entry.
+func (h *Helper) getUints(ifd Ifd, tag Tag) ([]uint32, error) {
+	v, err := h.GetValue(ifd, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	switch vs := v.(type) {
+	case []uint32:
+		return vs, nil
+	case []uint16:
+		out := make([]uint32, len(vs))
+		for i := range vs {
+			out[i] = uint32(vs[i])
+		}
+		return out, nil
+	}
+	return nil, h.entryError(ifd, tag, FormatUnsignedLong, ErrValueNotMatch)
+}
+
+// getUint returns the first value of a SHORT or LONG entry, or def when it
+// cannot be read.
+func (h *Helper) getUint(ifd Ifd, tag Tag, def uint32) uint32 {
+	vs, err := h.getUints(ifd, tag)
+	if err != nil || len(vs) == 0 {
+		return def
+	}
+	return vs[0]
+}
+
+// ThumbnailQuality is the JPEG quality used by RegenerateThumbnail.
+const ThumbnailQuality = 85
+
+const compressionJPEG = 6
+
+// ifd1StripTags are the tags describing an uncompressed IFD1 image.
+var ifd1StripTags = []Tag{
+	EXIF_TAG_IMAGE_WIDTH,
+	EXIF_TAG_IMAGE_LENGTH,
+	EXIF_TAG_BITS_PER_SAMPLE,
+	EXIF_TAG_PHOTOMETRIC_INTERPRETATION,
+	EXIF_TAG_STRIP_OFFSETS,
+	EXIF_TAG_SAMPLES_PER_PIXEL,
+	EXIF_TAG_ROWS_PER_STRIP,
+	EXIF_TAG_STRIP_BYTE_COUNTS,
+	EXIF_TAG_PLANAR_CONFIGURATION,
+}
+
+// SetThumbnail replaces the thumbnail with the given JPEG image. IFD1 is
+// updated to describe a JPEG thumbnail, and the change is written out by
+// Save.
+func (d *Data) SetThumbnail(thumb []byte) error {
+	if _, err := jpeg.DecodeConfig(bytes.NewReader(thumb)); err != nil {
+		return err
+	}
+	if len(thumb) > jpegMaxSegment {
+		return ErrExifTooLarge
+	}
+
+	for _, tag := range ifd1StripTags {
+		d.Delete(Ifd1, tag)
+	}
+
+	if err := d.Set(Ifd1, EXIF_TAG_COMPRESSION, uint16(compressionJPEG)); err != nil {
+		return err
+	}
+	// libexif computes the actual offset and length when saving, the length
+	// is kept in sync for the readers of Raw.
+	if err := d.Set(Ifd1, EXIF_TAG_JPEG_INTERCHANGE_FORMAT_LENGTH, uint32(len(thumb))); err != nil {
+		return err
+	}
+
+	// The resolution is mandatory in IFD1.
+	h := NewHelper(d)
+	defaults := []struct {
+		tag   Tag
+		value interface{}
+	}{
+		{EXIF_TAG_X_RESOLUTION, UnsignedRational{Numerator: 72, Denominator: 1}},
+		{EXIF_TAG_Y_RESOLUTION, UnsignedRational{Numerator: 72, Denominator: 1}},
+		{EXIF_TAG_RESOLUTION_UNIT, uint16(2)},
+	}
+	for _, def := range defaults {
+		if h.GetEntry(uint16(Ifd1), uint16(def.tag)) != nil {
+			continue
+		}
+		if err := d.Set(Ifd1, def.tag, def.value); err != nil {
+			return err
+		}
+	}
+
+	d.thumbnail = append([]byte(nil), thumb...)
+	return nil
+}
+
+// RegenerateThumbnail replaces the thumbnail with a copy of img scaled down
+// to fit in a maxDim by maxDim square.
+func (d *Data) RegenerateThumbnail(img image.Image, maxDim int) error {
+	if maxDim <= 0 {
+		return ErrValueTooSmall
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return ErrValueTooSmall
+	}
+	if w > maxDim || h > maxDim {
+		if w >= h {
+			w, h = maxDim, (h*maxDim+w-1)/w
+		} else {
+			w, h = (w*maxDim+h-1)/h, maxDim
+		}
+	}
+
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, scaleImage(img, w, h), &jpeg.Options{Quality: ThumbnailQuality})
+	if err != nil {
+		return err
+	}
+
+	return d.SetThumbnail(buf.Bytes())
+}
+
+// RemoveThumbnail drops the thumbnail and every IFD1 entry.
+func (d *Data) RemoveThumbnail() {
+	d.thumbnail = nil
+	for _, e := range d.Raw {
+		if e.Ifd == Ifd1 {
+			d.removeEntry(e.Ifd, e.Tag)
+		}
+	}
+}
+
+// scaleImage resizes img to w by h, averaging the source pixels covered by
+// each destination pixel.
+func scaleImage(img image.Image, w, h int) *image.RGBA {
+	b := img.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		y0, y1 := y*sh/h, (y+1)*sh/h
+		if y1 == y0 {
+			y1++
+		}
+		for x := 0; x < w; x++ {
+			x0, x1 := x*sw/w, (x+1)*sw/w
+			if x1 == x0 {
+				x1++
+			}
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(b.Min.X+sx, b.Min.Y+sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			out.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return out
+}
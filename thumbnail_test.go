@@ -0,0 +1,108 @@
+package exif
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThumbnail(t *testing.T) {
+	data, err := Read("_examples/resources/test.jpg")
+	require.NoError(t, err)
+
+	thumb, err := data.Thumbnail()
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(thumb, []byte{0xff, 0xd8}))
+
+	img, err := data.ThumbnailImage()
+	require.NoError(t, err)
+	assert.Equal(t, 160, img.Bounds().Dx())
+	assert.Equal(t, 120, img.Bounds().Dy())
+
+	_, err = New().Thumbnail()
+	assert.Equal(t, ErrNoThumbnail, err)
+}
+
+func TestStripThumbnail(t *testing.T) {
+	pix := []byte{
+		0xff, 0, 0, 0, 0xff, 0,
+		0, 0, 0xff, 0xff, 0xff, 0xff,
+	}
+
+	data := New()
+	data.rawExif = append([]byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08"), pix...)
+	require.NoError(t, data.Set(Ifd1, EXIF_TAG_COMPRESSION, uint16(compressionNone)))
+	require.NoError(t, data.Set(Ifd1, EXIF_TAG_PHOTOMETRIC_INTERPRETATION, uint16(photometricRGB)))
+	require.NoError(t, data.Set(Ifd1, EXIF_TAG_SAMPLES_PER_PIXEL, uint16(3)))
+	require.NoError(t, data.Set(Ifd1, EXIF_TAG_BITS_PER_SAMPLE, []uint16{8, 8, 8}))
+	require.NoError(t, data.Set(Ifd1, EXIF_TAG_IMAGE_WIDTH, uint16(2)))
+	require.NoError(t, data.Set(Ifd1, EXIF_TAG_IMAGE_LENGTH, uint16(2)))
+	require.NoError(t, data.Set(Ifd1, EXIF_TAG_STRIP_OFFSETS, []uint32{8, 14}))
+	require.NoError(t, data.Set(Ifd1, EXIF_TAG_STRIP_BYTE_COUNTS, []uint16{6, 6}))
+
+	thumb, err := data.Thumbnail()
+	require.NoError(t, err)
+	assert.Equal(t, pix, thumb)
+
+	img, err := data.ThumbnailImage()
+	require.NoError(t, err)
+	assert.Equal(t, color.RGBA{0, 0, 0xff, 0xff}, img.At(0, 1))
+	assert.Equal(t, color.RGBA{0, 0xff, 0, 0xff}, img.At(1, 0))
+
+	// The strips are too short for the dimensions, whose product overflows
+	// an int.
+	for _, size := range [][2]uint32{{0x80000000, 0x80000000}, {0xffffffff, 1}, {3, 2}} {
+		require.NoError(t, data.Set(Ifd1, EXIF_TAG_IMAGE_WIDTH, size[0]))
+		require.NoError(t, data.Set(Ifd1, EXIF_TAG_IMAGE_LENGTH, size[1]))
+		_, err = data.ThumbnailImage()
+		assert.Equal(t, ErrUnsupportedThumbnail, err, "%v", size)
+	}
+
+	require.NoError(t, data.Set(Ifd1, EXIF_TAG_STRIP_BYTE_COUNTS, []uint16{6, 7}))
+	_, err = data.Thumbnail()
+	assert.Equal(t, ErrValueTooSmall, err)
+}
+
+func TestSetThumbnail(t *testing.T) {
+	src, err := os.ReadFile("_examples/resources/testlocation.jpg")
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "test.jpg")
+	require.NoError(t, os.WriteFile(file, src, 0644))
+
+	data, err := Read(file)
+	require.NoError(t, err)
+
+	img := image.NewRGBA(image.Rect(0, 0, 400, 100))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{0x80, 0x40, 0x20, 0xff}), image.Point{}, draw.Src)
+	require.NoError(t, data.RegenerateThumbnail(img, 160))
+	require.NoError(t, data.SaveFile(file))
+
+	saved, err := Read(file)
+	require.NoError(t, err)
+	thumb, err := saved.Thumbnail()
+	require.NoError(t, err)
+	assert.Equal(t, data.thumbnail, thumb)
+	small, err := saved.ThumbnailImage()
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 160, 40), small.Bounds())
+
+	saved.RemoveThumbnail()
+	require.NoError(t, saved.SaveFile(file))
+
+	saved, err = Read(file)
+	require.NoError(t, err)
+	_, err = saved.Thumbnail()
+	assert.Equal(t, ErrNoThumbnail, err)
+	for _, e := range saved.Raw {
+		assert.NotEqual(t, Ifd1, e.Ifd)
+	}
+
+	assert.Error(t, data.SetThumbnail([]byte("not a jpeg")))
+}
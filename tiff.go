@@ -0,0 +1,463 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// maxExifBlock is the largest EXIF block libexif looks at, the payload of a
+// JPEG segment.
+const maxExifBlock = 0xfffe
+
+// maxRecursionCost bounds the work done following IFD pointers, see
+// tiffReader.readIfd.
+const maxRecursionCost = 170
+
+// maxIfds bounds the number of IFDs read from a block.
+const maxIfds = 256
+
+// formatIfd is the TIFF format of the entries holding IFD offsets, which
+// libexif does not know.
+const formatIfd EntryFormat = 13
+
+// keptTags are the tags libexif does not know which are loaded anyway,
+// because the helpers read them.
+var keptTags = map[IfdTag]bool{
+	NewIfdTag(uint16(IfdExif), uint16(EXIF_TAG_SENSITIVITY_TYPE)):            true,
+	NewIfdTag(uint16(IfdExif), uint16(EXIF_TAG_STANDARD_OUTPUT_SENSITIVITY)): true,
+	NewIfdTag(uint16(IfdExif), uint16(EXIF_TAG_RECOMMENDED_EXPOSURE_INDEX)):  true,
+}
+
+// size returns the size in bytes of one component of the format, or 0 when
+// the format is unknown.
+func (f EntryFormat) size() int {
+	switch f {
+	case FormatUnsignedByte, FormatAscii, FormatSignedByte, FormatUndefined:
+		return 1
+	case FormatUnsignedShort, FormatSignedShort:
+		return 2
+	case FormatUnsignedLong, FormatSignedLong, FormatFloat:
+		return 4
+	case FormatUnsignedRational, FormatSignedRational, FormatDouble:
+		return 8
+	}
+	return 0
+}
+
+// tiffReader walks the IFDs of an EXIF block following the rules libexif uses
+// when loading: pointers and thumbnail locations are followed instead of
+// being returned as entries, each IFD is loaded once, and entries pointing
+// out of the block are dropped. Unlike libexif, every occurrence of a tag in
+// an IFD is kept, in the order of the file.
+//
+// The IFDs libexif does not load, the ones listed by SubIFDs entries or
+// entries of the TIFF IFD format and the ones chained after IFD1, are
+// numbered from IfdMaxCount as they are met. They are loaded once per offset,
+// and keep their unknown tags.
+type tiffReader struct {
+	// b is the TIFF structure, starting with the byte order mark.
+	b     []byte
+	order binary.ByteOrder
+
+	// ignoreUnknown drops the tags libexif does not know in their IFD, except
+	// keptTags.
+	ignoreUnknown bool
+
+	entries   map[Ifd][]Entry
+	thumbnail []byte
+
+	// ifds describes the IFDs loaded, parents first.
+	ifds    []IfdInfo
+	offsets map[uint32]bool
+	nextIfd Ifd
+
+	// layout, when set, records where the parts of the block are, including
+	// the ones that are not loaded.
+	layout *Layout
+
+	// diagnostics lists what was skipped, in the words of libexif.
+	diagnostics []Diagnostic
+}
+
+// readExifBlock parses an EXIF block starting with the "Exif\x00\x00" header.
+// The offsets of the entries are relative to the TIFF header.
+func readExifBlock(block []byte, ignoreUnknown bool) (*tiffReader, error) {
+	r, err := newTiffReader(block, ignoreUnknown)
+	if err != nil {
+		return nil, err
+	}
+	r.read()
+	return r, nil
+}
+
+// newTiffReader checks the header of an EXIF block, see readExifBlock.
+func newTiffReader(block []byte, ignoreUnknown bool) (*tiffReader, error) {
+	if !bytes.HasPrefix(block, exifHeader) {
+		return nil, ErrNoExifData
+	}
+	if len(block) > maxExifBlock {
+		block = block[:maxExifBlock]
+	}
+
+	r := &tiffReader{
+		b:             block[len(exifHeader):],
+		ignoreUnknown: ignoreUnknown,
+		entries:       make(map[Ifd][]Entry),
+		offsets:       make(map[uint32]bool),
+		nextIfd:       IfdMaxCount,
+	}
+	if len(r.b) < 8 {
+		return nil, ErrNoExifData
+	}
+	switch string(r.b[:2]) {
+	case "II":
+		r.order = binary.LittleEndian
+	case "MM":
+		r.order = binary.BigEndian
+	default:
+		return nil, ErrNoExifData
+	}
+	if r.order.Uint16(r.b[2:]) != 0x002a {
+		return nil, ErrNoExifData
+	}
+	return r, nil
+}
+
+func (r *tiffReader) read() {
+	r.addRegion(Region{Kind: RegionHeader, Length: 8})
+
+	next := r.readIfd(IfdInfo{Ifd: Ifd0, Parent: Ifd0}, r.order.Uint32(r.b[4:]), 0)
+
+	// IFD1 follows IFD0.
+	if next != 0 {
+		next = r.readIfd(IfdInfo{Ifd: Ifd1, Parent: Ifd0}, next, 0)
+		r.readChain(Ifd1, next, 0)
+	}
+}
+
+// readIfd loads the IFD at offset into info.Ifd, and returns the offset of
+// the next IFD, 0 when there is none. cost grows with the depth and the size
+// of the IFDs that lead there, to stop malicious files from making the reader
+// walk the same IFDs over and over.
+func (r *tiffReader) readIfd(info IfdInfo, offset uint32, cost int) uint32 {
+	ifd := info.Ifd
+	if cost > maxRecursionCost {
+		r.logIfd(LogCorruptData, ifd, "Deep/expensive recursion detected!")
+		return 0
+	}
+	if uint64(offset)+2 > uint64(len(r.b)) {
+		r.logIfd(LogCorruptData, ifd, "Tag data past end of buffer (%d > %d)", uint64(offset)+2, len(r.b))
+		r.addRegion(Region{Kind: RegionIfd, Ifd: ifd, Offset: int(offset), Length: 2})
+		return 0
+	}
+	r.addInfo(info)
+	r.offsets[offset] = true
+
+	n := int(r.order.Uint16(r.b[offset:]))
+	start := int(offset) + 2
+	r.addIfd(ifd, start-2, n)
+
+	var next uint32
+	if pos := start + 12*n; pos+4 <= len(r.b) {
+		next = r.order.Uint32(r.b[pos:])
+	}
+	if start+12*n > len(r.b) {
+		n = (len(r.b) - start) / 12
+		r.logIfd(LogDebug, ifd, "Short data; only loading %d entries...", n)
+	}
+
+	var thumbOffset, thumbLength uint32
+	for i := 0; i < n; i++ {
+		raw := r.b[start+12*i : start+12*i+12]
+		tag := Tag(r.order.Uint16(raw))
+
+		switch tag {
+		case EXIF_TAG_EXIF_IFD_POINTER,
+			EXIF_TAG_GPS_INFO_IFD_POINTER,
+			EXIF_TAG_INTEROPERABILITY_IFD_POINTER,
+			EXIF_TAG_JPEG_INTERCHANGE_FORMAT,
+			EXIF_TAG_JPEG_INTERCHANGE_FORMAT_LENGTH:
+			o := r.order.Uint32(raw[8:])
+			if uint64(o) >= uint64(len(r.b)) {
+				r.logEntry(LogCorruptData, ifd, tag, "Tag data past end of buffer (%d > %d)", o, len(r.b))
+				r.addPointed(ifd, tag, o, thumbOffset)
+				return next
+			}
+
+			switch tag {
+			case EXIF_TAG_EXIF_IFD_POINTER:
+				r.readSubIfd(ifd, IfdExif, tag, o, cost+levelCost(n))
+			case EXIF_TAG_GPS_INFO_IFD_POINTER:
+				r.readSubIfd(ifd, IfdGps, tag, o, cost+levelCost(n))
+			case EXIF_TAG_INTEROPERABILITY_IFD_POINTER:
+				r.readSubIfd(ifd, IfdInterOperability, tag, o, cost+levelCost(n))
+			case EXIF_TAG_JPEG_INTERCHANGE_FORMAT:
+				thumbOffset = o
+			case EXIF_TAG_JPEG_INTERCHANGE_FORMAT_LENGTH:
+				thumbLength = o
+			}
+			if thumbOffset != 0 && thumbLength != 0 {
+				r.readThumbnail(ifd, thumbOffset, thumbLength)
+			}
+			continue
+		}
+		r.addValue(ifd, raw)
+
+		// SubIFDs holds LONG or IFD offsets, private IFDs are of the IFD
+		// format whatever their tag.
+		if format := EntryFormat(r.order.Uint16(raw[2:])); format == formatIfd || tag == EXIF_TAG_SUB_IFDS && format == FormatUnsignedLong {
+			r.readSubIfds(ifd, raw, cost+levelCost(n))
+		}
+
+		if tag.Name(ifd) == "" {
+			// Photoshop writes empty entries.
+			if bytes.Equal(raw[:4], []byte{0, 0, 0, 0}) {
+				r.logIfd(LogDebug, ifd, "Skipping empty entry at position %d in '%s'.", i, ifd)
+				continue
+			}
+			if r.ignoreUnknown && ifd < IfdMaxCount && !keptTags[NewIfdTag(uint16(ifd), uint16(tag))] {
+				r.logEntry(LogDebug, ifd, tag, "Unknown tag 0x%04x (entry %d in '%s').", uint16(tag), i, ifd)
+				continue
+			}
+		}
+
+		if e, ok := r.readEntry(ifd, start+12*i); ok {
+			r.entries[ifd] = append(r.entries[ifd], e)
+		}
+	}
+
+	return next
+}
+
+// readSubIfd loads the IFD pointed to by tag from parent, unless it is
+// already loaded.
+func (r *tiffReader) readSubIfd(parent, ifd Ifd, tag Tag, offset uint32, cost int) {
+	if parent == ifd {
+		r.logIfd(LogDebug, ifd, "Recursive entry in IFD '%s' detected. Skipping...", ifd)
+		return
+	}
+	if len(r.entries[ifd]) != 0 {
+		r.logIfd(LogDebug, ifd, "Attempt to load IFD '%s' multiple times detected. Skipping...", ifd)
+		return
+	}
+	r.readIfd(IfdInfo{Ifd: ifd, Parent: parent, Tag: tag}, offset, cost)
+}
+
+// readSubIfds loads the IFDs listed by the entry raw of parent, and the
+// IFDs chained after them.
+func (r *tiffReader) readSubIfds(parent Ifd, raw []byte, cost int) {
+	count := uint64(r.order.Uint32(raw[4:]))
+	offsets := raw[8:12]
+	switch {
+	case count == 0:
+		return
+	case count > 1:
+		o := uint64(r.order.Uint32(raw[8:]))
+		if o >= uint64(len(r.b)) || 4*count > uint64(len(r.b))-o {
+			return
+		}
+		offsets = r.b[o : o+4*count]
+	}
+
+	for i := 0; i < len(offsets); i += 4 {
+		offset := r.order.Uint32(offsets[i:])
+		ifd, ok := r.newIfd(offset, cost)
+		if !ok {
+			continue
+		}
+		info := IfdInfo{
+			Ifd:    ifd,
+			Parent: parent,
+			Tag:    Tag(r.order.Uint16(raw)),
+			Index:  i / 4,
+		}
+		r.readChain(ifd, r.readIfd(info, offset, cost), cost)
+	}
+}
+
+// readChain loads the IFDs linked after ifd, next being the offset of the
+// first one.
+func (r *tiffReader) readChain(ifd Ifd, next uint32, cost int) {
+	for next != 0 {
+		child, ok := r.newIfd(next, cost)
+		if !ok {
+			return
+		}
+		next = r.readIfd(IfdInfo{Ifd: child, Parent: ifd}, next, cost)
+		ifd = child
+	}
+}
+
+// newIfd numbers the IFD at offset, unless it cannot be loaded.
+func (r *tiffReader) newIfd(offset uint32, cost int) (Ifd, bool) {
+	if cost > maxRecursionCost || uint64(offset)+2 > uint64(len(r.b)) || r.offsets[offset] {
+		return 0, false
+	}
+	if len(r.ifds) >= maxIfds || r.nextIfd == math.MaxUint16 {
+		return 0, false
+	}
+	ifd := r.nextIfd
+	r.nextIfd++
+	return ifd, true
+}
+
+// addInfo records the IFD described by info, once.
+func (r *tiffReader) addInfo(info IfdInfo) {
+	for i := range r.ifds {
+		if r.ifds[i].Ifd == info.Ifd {
+			return
+		}
+	}
+	if info.Ifd != Ifd0 {
+		for i := range r.ifds {
+			if r.ifds[i].Ifd == info.Parent {
+				r.ifds[i].Children = append(r.ifds[i].Children, info.Ifd)
+			}
+		}
+	}
+	r.ifds = append(r.ifds, info)
+}
+
+// readEntry decodes the IFD entry at pos.
+func (r *tiffReader) readEntry(ifd Ifd, pos int) (Entry, bool) {
+	raw := r.b[pos : pos+12]
+	e := Entry{
+		Ifd:        ifd,
+		Tag:        Tag(r.order.Uint16(raw)),
+		Format:     EntryFormat(r.order.Uint16(raw[2:])),
+		Components: int(r.order.Uint32(raw[4:])),
+		order:      r.order,
+	}
+
+	size := uint64(e.Format.size()) * uint64(r.order.Uint32(raw[4:]))
+	if size == 0 || size > math.MaxUint32 {
+		return e, false
+	}
+
+	var data []byte
+	if size > 4 {
+		offset := uint64(r.order.Uint32(raw[8:]))
+		if offset >= uint64(len(r.b)) {
+			r.logEntry(LogDebug, ifd, e.Tag, "Tag starts past end of buffer (%d > %d)", offset, len(r.b))
+			return e, false
+		}
+		if size > uint64(len(r.b))-offset {
+			r.logEntry(LogDebug, ifd, e.Tag, "Tag data goes past end of buffer (%d > %d)", offset+size, len(r.b))
+			return e, false
+		}
+		data = r.b[offset : offset+size]
+		e.Offset = int(offset)
+	} else {
+		data = raw[8 : 8+size]
+		e.Offset = pos + 8
+	}
+
+	e.Raw = append([]byte(nil), data...)
+	e.Length = int(size)
+	return e, true
+}
+
+func (r *tiffReader) readThumbnail(ifd Ifd, offset, length uint32) {
+	r.addRegion(Region{Kind: RegionThumbnail, Ifd: ifd, Offset: int(offset), Length: int(length)})
+	if uint64(offset) >= uint64(len(r.b)) {
+		r.logIfd(LogDebug, ifd, "Bogus thumbnail offset (%d).", offset)
+		return
+	}
+	if uint64(length) > uint64(len(r.b))-uint64(offset) {
+		r.logIfd(LogDebug, ifd, "Bogus thumbnail size (%d), max would be %d.", length, uint64(len(r.b))-uint64(offset))
+		return
+	}
+	r.thumbnail = append([]byte(nil), r.b[offset:offset+length]...)
+}
+
+// levelCost is the recursion cost of following a pointer from an IFD of n
+// entries: work grows as 1.1 to the power of the cost.
+func levelCost(n int) int {
+	return int(math.Ceil(math.Log(float64(n)+0.1) / math.Log(1.1)))
+}
+
+// logIfd records a diagnostic about ifd.
+func (r *tiffReader) logIfd(code LogCode, ifd Ifd, format string, args ...interface{}) {
+	r.diagnostics = append(r.diagnostics, Diagnostic{
+		Code:    code,
+		Domain:  "ExifData",
+		Message: fmt.Sprintf(format, args...),
+		Ifd:     ifd,
+		HasIfd:  true,
+	})
+}
+
+// logEntry records a diagnostic about the entry tag of ifd.
+func (r *tiffReader) logEntry(code LogCode, ifd Ifd, tag Tag, format string, args ...interface{}) {
+	r.logIfd(code, ifd, format, args...)
+	g := &r.diagnostics[len(r.diagnostics)-1]
+	g.Tag, g.HasTag = tag, true
+}
+
+func (r *tiffReader) addRegion(region Region) {
+	if r.layout != nil {
+		r.layout.Regions = append(r.layout.Regions, region)
+	}
+}
+
+// addIfd records the IFD of n entries at offset, and its link to the next
+// IFD.
+func (r *tiffReader) addIfd(ifd Ifd, offset, n int) {
+	if r.layout == nil {
+		return
+	}
+
+	l := IfdLayout{
+		Ifd:     ifd,
+		Offset:  offset,
+		Entries: n,
+	}
+	if next := offset + 2 + 12*n; next+4 <= len(r.b) {
+		l.Next = int(r.order.Uint32(r.b[next:]))
+	}
+	r.layout.Ifds = append(r.layout.Ifds, l)
+	r.addRegion(Region{Kind: RegionIfd, Ifd: ifd, Offset: offset, Length: 2 + 12*n + 4})
+}
+
+// addValue records the value of the entry raw when it is stored out of the
+// IFD.
+func (r *tiffReader) addValue(ifd Ifd, raw []byte) {
+	if r.layout == nil {
+		return
+	}
+
+	size := uint64(EntryFormat(r.order.Uint16(raw[2:])).size()) * uint64(r.order.Uint32(raw[4:]))
+	if size <= 4 {
+		return
+	}
+	if size > math.MaxInt32 {
+		size = math.MaxInt32
+	}
+	r.addRegion(Region{
+		Kind:   RegionValue,
+		Ifd:    ifd,
+		Tag:    Tag(r.order.Uint16(raw)),
+		Offset: int(r.order.Uint32(raw[8:])),
+		Length: int(size),
+	})
+}
+
+// addPointed records what the pointer or thumbnail tag of ifd points to
+// when offset is out of the block.
+func (r *tiffReader) addPointed(ifd Ifd, tag Tag, offset, thumbOffset uint32) {
+	switch tag {
+	case EXIF_TAG_EXIF_IFD_POINTER:
+		r.addRegion(Region{Kind: RegionIfd, Ifd: IfdExif, Offset: int(offset), Length: 2})
+	case EXIF_TAG_GPS_INFO_IFD_POINTER:
+		r.addRegion(Region{Kind: RegionIfd, Ifd: IfdGps, Offset: int(offset), Length: 2})
+	case EXIF_TAG_INTEROPERABILITY_IFD_POINTER:
+		r.addRegion(Region{Kind: RegionIfd, Ifd: IfdInterOperability, Offset: int(offset), Length: 2})
+	case EXIF_TAG_JPEG_INTERCHANGE_FORMAT:
+		r.addRegion(Region{Kind: RegionThumbnail, Ifd: ifd, Offset: int(offset)})
+	case EXIF_TAG_JPEG_INTERCHANGE_FORMAT_LENGTH:
+		// offset is the length of the thumbnail.
+		r.addRegion(Region{Kind: RegionThumbnail, Ifd: ifd, Offset: int(thumbOffset), Length: int(offset)})
+	}
+}
@@ -0,0 +1,76 @@
+package exif
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadExifBlock(t *testing.T) {
+	for _, file := range []string{
+		"_examples/resources/test.jpg",
+		"_examples/resources/testlocation.jpg",
+	} {
+		data, err := Read(file)
+		require.NoError(t, err)
+
+		r, err := readExifBlock(data.rawExif, true)
+		require.NoError(t, err)
+		assert.Equal(t, data.Order, r.order)
+		assert.Equal(t, data.thumbnail, r.thumbnail)
+
+		// libexif adds the entries required by the specification.
+		for ifd := Ifd0; ifd < IfdMaxCount; ifd++ {
+			for _, e := range r.entries[ifd] {
+				got, ok := data.Raw[NewIfdTag(uint16(ifd), uint16(e.Tag))]
+				if assert.True(t, ok, "%s: %s", file, e.String()) && got.Format == e.Format {
+					assert.Equal(t, got.Raw, e.Raw, "%s: %s", file, e.String())
+				}
+			}
+		}
+	}
+}
+
+func TestReadExifBlockMalformed(t *testing.T) {
+	le := binary.LittleEndian
+	block := func(ifd0 ...byte) []byte {
+		b := append([]byte("Exif\x00\x00II*\x00"), 8, 0, 0, 0)
+		return append(b, ifd0...)
+	}
+	entry := func(tag, format uint16, count, value uint32) []byte {
+		b := make([]byte, 12)
+		le.PutUint16(b, tag)
+		le.PutUint16(b[2:], format)
+		le.PutUint32(b[4:], count)
+		le.PutUint32(b[8:], value)
+		return b
+	}
+
+	for _, b := range [][]byte{nil, []byte("Exif\x00\x00"), []byte("Exif\x00\x00XX*\x00\x08\x00\x00\x00")} {
+		_, err := readExifBlock(b, true)
+		assert.Equal(t, ErrNoExifData, err)
+	}
+
+	// An IFD0 announcing more entries than the block holds.
+	b := block(append([]byte{3, 0}, entry(uint16(EXIF_TAG_ORIENTATION), uint16(FormatUnsignedShort), 1, 6)...)...)
+	r, err := readExifBlock(b, true)
+	require.NoError(t, err)
+	require.Len(t, r.entries[Ifd0], 1)
+	assert.Equal(t, []byte{6, 0}, r.entries[Ifd0][0].Raw)
+
+	// Values out of the block, duplicated tags and an IFD pointing to itself.
+	ifd0 := []byte{4, 0}
+	ifd0 = append(ifd0, entry(uint16(EXIF_TAG_MAKE), uint16(FormatAscii), 100, 0x1000)...)
+	ifd0 = append(ifd0, entry(uint16(EXIF_TAG_ORIENTATION), uint16(FormatUnsignedShort), 1, 1)...)
+	ifd0 = append(ifd0, entry(uint16(EXIF_TAG_ORIENTATION), uint16(FormatUnsignedShort), 1, 3)...)
+	ifd0 = append(ifd0, entry(uint16(EXIF_TAG_EXIF_IFD_POINTER), uint16(FormatUnsignedLong), 1, 8)...)
+	ifd0 = append(ifd0, 0, 0, 0, 0)
+	r, err = readExifBlock(block(ifd0...), true)
+	require.NoError(t, err)
+	require.Len(t, r.entries[Ifd0], 2)
+	assert.Equal(t, []byte{1, 0}, r.entries[Ifd0][0].Raw)
+	assert.Equal(t, []byte{3, 0}, r.entries[Ifd0][1].Raw)
+	assert.Empty(t, r.entries[IfdExif])
+}
@@ -0,0 +1,338 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"unicode/utf16"
+)
+
+// FileSource is the value of the FileSource tag.
+type FileSource uint8
+
+// File sources.
+const (
+	FileSourceOthers             FileSource = 0
+	FileSourceTransparentScanner FileSource = 1
+	FileSourceReflexScanner      FileSource = 2
+	FileSourceDSC                FileSource = 3
+)
+
+var fileSourceNames = map[uint16]string{
+	0: "Others",
+	1: "Scanner of transparent type",
+	2: "Scanner of reflex type",
+	3: "DSC",
+}
+
+func (v FileSource) String() string {
+	return enumName(fileSourceNames, "FileSource", uint16(v))
+}
+
+// SceneType is the value of the SceneType tag.
+type SceneType uint8
+
+// Scene types.
+const (
+	SceneTypeDirectlyPhotographed SceneType = 1
+)
+
+var sceneTypeNames = map[uint16]string{
+	1: "Directly photographed",
+}
+
+func (v SceneType) String() string {
+	return enumName(sceneTypeNames, "SceneType", uint16(v))
+}
+
+// CFAColor is a color filter of the sensor, in a CFAPattern.
+type CFAColor uint8
+
+// Color filters.
+const (
+	CFARed     CFAColor = 0
+	CFAGreen   CFAColor = 1
+	CFABlue    CFAColor = 2
+	CFACyan    CFAColor = 3
+	CFAMagenta CFAColor = 4
+	CFAYellow  CFAColor = 5
+	CFAWhite   CFAColor = 6
+)
+
+var cfaColorNames = map[uint16]string{
+	0: "Red",
+	1: "Green",
+	2: "Blue",
+	3: "Cyan",
+	4: "Magenta",
+	5: "Yellow",
+	6: "White",
+}
+
+func (v CFAColor) String() string {
+	return enumName(cfaColorNames, "CFAColor", uint16(v))
+}
+
+// CFAPattern is the repeated pattern of the color filters of the sensor, by
+// row.
+type CFAPattern [][]CFAColor
+
+// ResponseTable is the value of the OECF and SpatialFrequencyResponse tags:
+// a table with a name for each column.
+type ResponseTable struct {
+	Columns []string
+	Rows    [][]float64
+}
+
+// DeviceSettings is the value of the DeviceSettingDescription tag.
+type DeviceSettings struct {
+	// Columns and Rows are the size of the display of the settings.
+	Columns  int
+	Rows     int
+	Settings []string
+}
+
+// componentNames are the channels recorded in ComponentsConfiguration, 0
+// marking a channel that does not exist.
+var componentNames = []string{"", "Y", "Cb", "Cr", "R", "G", "B"}
+
+// checkUndefined makes sure e is an UNDEFINED value of at least n bytes.
+func (e *Entry) checkUndefined(n int) error {
+	if e.Format != FormatUndefined {
+		return e.newError(FormatUndefined, ErrFormatNotMatch)
+	}
+	if len(e.Raw) < n {
+		return e.newError(FormatUndefined, ErrValueTooSmall)
+	}
+	return nil
+}
+
+// ReadAsVersion decodes a version recorded as 4 digits, such as "0232" for
+// ExifVersion, FlashPixVersion or InteroperabilityVersion.
+func (e *Entry) ReadAsVersion() (string, error) {
+	if e.Format == FormatAscii {
+		return strings.TrimRight(string(e.Raw), "\x00 "), nil
+	}
+	if err := e.checkUndefined(4); err != nil {
+		return "", err
+	}
+	return string(e.Raw[:4]), nil
+}
+
+// ReadAsComponentsConfiguration decodes the channels of each component, such
+// as "YCbCr" or "RGB".
+func (e *Entry) ReadAsComponentsConfiguration() (string, error) {
+	if err := e.checkUndefined(4); err != nil {
+		return "", err
+	}
+
+	var s strings.Builder
+	for _, c := range e.Raw[:4] {
+		if int(c) >= len(componentNames) {
+			return "", e.newError(FormatUndefined, ErrValueNotMatch)
+		}
+		s.WriteString(componentNames[c])
+	}
+	return s.String(), nil
+}
+
+// ReadAsFileSource decodes the FileSource tag.
+func (e *Entry) ReadAsFileSource() (FileSource, error) {
+	if err := e.checkUndefined(1); err != nil {
+		return 0, err
+	}
+	return FileSource(e.Raw[0]), nil
+}
+
+// ReadAsSceneType decodes the SceneType tag.
+func (e *Entry) ReadAsSceneType() (SceneType, error) {
+	if err := e.checkUndefined(1); err != nil {
+		return 0, err
+	}
+	return SceneType(e.Raw[0]), nil
+}
+
+// ReadAsCFAPattern decodes the CFAPattern tag: the width and height of the
+// pattern, followed by its colors. Some cameras record the size big endian
+// whatever the byte order of the data.
+func (e *Entry) ReadAsCFAPattern() (CFAPattern, error) {
+	if err := e.checkUndefined(4); err != nil {
+		return nil, err
+	}
+
+	n := len(e.Raw) - 4
+	var w, h int
+	for _, order := range []binary.ByteOrder{e.byteOrder(), swapOrder(e.byteOrder())} {
+		w, h = int(order.Uint16(e.Raw)), int(order.Uint16(e.Raw[2:]))
+		if w*h == n {
+			break
+		}
+	}
+	if w*h != n {
+		return nil, e.newError(FormatUndefined, ErrLengthNotMatch)
+	}
+
+	out := make(CFAPattern, h)
+	for y := range out {
+		out[y] = make([]CFAColor, w)
+		for x := range out[y] {
+			out[y][x] = CFAColor(e.Raw[4+y*w+x])
+		}
+	}
+	return out, nil
+}
+
+// ReadAsOECF decodes the OECF tag, a table of signed rationals.
+func (e *Entry) ReadAsOECF() (*ResponseTable, error) {
+	return e.readResponseTable(true)
+}
+
+// ReadAsSpatialFrequencyResponse decodes the SpatialFrequencyResponse tag, a
+// table of unsigned rationals.
+func (e *Entry) ReadAsSpatialFrequencyResponse() (*ResponseTable, error) {
+	return e.readResponseTable(false)
+}
+
+// readResponseTable decodes the number of columns and rows, the names of the
+// columns as NUL terminated strings, then the values row by row.
+func (e *Entry) readResponseTable(signed bool) (*ResponseTable, error) {
+	if err := e.checkUndefined(4); err != nil {
+		return nil, err
+	}
+
+	order := e.byteOrder()
+	cols, rows := int(order.Uint16(e.Raw)), int(order.Uint16(e.Raw[2:]))
+	raw := e.Raw[4:]
+
+	t := &ResponseTable{Columns: make([]string, cols)}
+	for i := range t.Columns {
+		end := bytes.IndexByte(raw, 0)
+		if end < 0 {
+			return nil, e.newError(FormatUndefined, ErrValueTooSmall)
+		}
+		t.Columns[i] = string(raw[:end])
+		raw = raw[end+1:]
+	}
+
+	if uint64(len(raw)) < uint64(cols)*uint64(rows)*8 {
+		return nil, e.newError(FormatUndefined, ErrValueTooSmall)
+	}
+	t.Rows = make([][]float64, rows)
+	for y := range t.Rows {
+		t.Rows[y] = make([]float64, cols)
+		for x := range t.Rows[y] {
+			v := raw[(y*cols+x)*8:]
+			if signed {
+				r := SignedRational{int32(order.Uint32(v)), int32(order.Uint32(v[4:]))}
+				t.Rows[y][x] = r.Float64()
+			} else {
+				r := UnsignedRational{order.Uint32(v), order.Uint32(v[4:])}
+				t.Rows[y][x] = r.Float64()
+			}
+		}
+	}
+	return t, nil
+}
+
+// ReadAsDeviceSettings decodes the DeviceSettingDescription tag: the size of
+// the display, followed by the settings as NUL terminated UCS-2 strings.
+func (e *Entry) ReadAsDeviceSettings() (*DeviceSettings, error) {
+	if err := e.checkUndefined(4); err != nil {
+		return nil, err
+	}
+
+	order := e.byteOrder()
+	d := &DeviceSettings{
+		Columns: int(order.Uint16(e.Raw)),
+		Rows:    int(order.Uint16(e.Raw[2:])),
+	}
+
+	var s []uint16
+	for i := 4; i+1 < len(e.Raw); i += 2 {
+		c := order.Uint16(e.Raw[i:])
+		if c == 0 {
+			d.Settings = append(d.Settings, string(utf16.Decode(s)))
+			s = s[:0]
+			continue
+		}
+		s = append(s, c)
+	}
+	if len(s) != 0 {
+		d.Settings = append(d.Settings, string(utf16.Decode(s)))
+	}
+	return d, nil
+}
+
+// swapOrder returns the other byte order.
+func swapOrder(order binary.ByteOrder) binary.ByteOrder {
+	if order == binary.BigEndian {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
+
+// Decode returns the value of the entry as GetValue does, except for the
+// standard tags recorded as UNDEFINED, which are decoded:
+//
+//	ExifVersion, FlashPixVersion,
+//	InteroperabilityVersion  => string
+//	ComponentsConfiguration  => string
+//	FileSource               => FileSource
+//	SceneType                => SceneType
+//	CFAPattern               => CFAPattern
+//	OECF,
+//	SpatialFrequencyResponse => *ResponseTable
+//	DeviceSettingDescription => *DeviceSettings
+//	UserComment,
+//	GPSProcessingMethod,
+//	GPSAreaInformation       => string
+func (e *Entry) Decode() (interface{}, error) {
+	if e.Format != FormatUndefined {
+		return e.GetValue()
+	}
+
+	if e.Ifd == IfdGps {
+		switch e.Tag {
+		case EXIF_TAG_GPS_PROCESSING_METHOD, EXIF_TAG_GPS_AREA_INFORMATION:
+			return e.ReadAsEncodedString()
+		}
+		return e.GetValue()
+	}
+
+	switch e.Tag {
+	case EXIF_TAG_EXIF_VERSION, EXIF_TAG_FLASH_PIX_VERSION:
+		return e.ReadAsVersion()
+	case EXIF_TAG_INTEROPERABILITY_VERSION:
+		if e.Ifd == IfdInterOperability {
+			return e.ReadAsVersion()
+		}
+	case EXIF_TAG_COMPONENTS_CONFIGURATION:
+		return e.ReadAsComponentsConfiguration()
+	case EXIF_TAG_FILE_SOURCE:
+		return e.ReadAsFileSource()
+	case EXIF_TAG_SCENE_TYPE:
+		return e.ReadAsSceneType()
+	case EXIF_TAG_NEW_CFA_PATTERN, EXIF_TAG_CFA_PATTERN:
+		return e.ReadAsCFAPattern()
+	case EXIF_TAG_OECF:
+		return e.ReadAsOECF()
+	case EXIF_TAG_SPATIAL_FREQUENCY_RESPONSE:
+		return e.ReadAsSpatialFrequencyResponse()
+	case EXIF_TAG_DEVICE_SETTING_DESCRIPTION:
+		return e.ReadAsDeviceSettings()
+	case EXIF_TAG_USER_COMMENT:
+		return e.ReadAsEncodedString()
+	}
+	return e.GetValue()
+}
+
+// GetDecodedValue returns the value of the entry tag of ifd, see
+// Entry.Decode.
+func (h *Helper) GetDecodedValue(ifd Ifd, tag Tag) (interface{}, error) {
+	entry := h.GetEntry(uint16(ifd), uint16(tag))
+	if entry == nil {
+		return nil, h.entryError(ifd, tag, 0, ErrNotFoundEntry)
+	}
+
+	return entry.Decode()
+}
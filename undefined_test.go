@@ -0,0 +1,84 @@
+package exif
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeUndefined(t *testing.T) {
+	data := New()
+	set := func(ifd Ifd, tag Tag, raw []byte) *Entry {
+		e := data.NewEntry(ifd, tag)
+		require.NoError(t, e.SetUndefined(raw))
+		return e
+	}
+	decode := func(ifd Ifd, tag Tag, raw []byte) interface{} {
+		v, err := set(ifd, tag, raw).Decode()
+		require.NoError(t, err)
+		return v
+	}
+
+	assert.Equal(t, "0232", decode(IfdExif, EXIF_TAG_EXIF_VERSION, []byte("0232")))
+	assert.Equal(t, "0100", decode(IfdExif, EXIF_TAG_FLASH_PIX_VERSION, []byte("0100")))
+	assert.Equal(t, "0100", decode(IfdInterOperability, EXIF_TAG_INTEROPERABILITY_VERSION, []byte("0100")))
+	assert.Equal(t, "YCbCr", decode(IfdExif, EXIF_TAG_COMPONENTS_CONFIGURATION, []byte{1, 2, 3, 0}))
+	assert.Equal(t, "RGB", decode(IfdExif, EXIF_TAG_COMPONENTS_CONFIGURATION, []byte{4, 5, 6, 0}))
+	assert.Equal(t, FileSourceDSC, decode(IfdExif, EXIF_TAG_FILE_SOURCE, []byte{3}))
+	assert.Equal(t, "DSC", FileSourceDSC.String())
+	assert.Equal(t, SceneTypeDirectlyPhotographed, decode(IfdExif, EXIF_TAG_SCENE_TYPE, []byte{1}))
+	assert.Equal(t, "ASCII text", decode(IfdExif, EXIF_TAG_USER_COMMENT, []byte("ASCII\x00\x00\x00ASCII text")))
+
+	bayer := CFAPattern{{CFARed, CFAGreen}, {CFAGreen, CFABlue}}
+	assert.Equal(t, bayer, decode(IfdExif, EXIF_TAG_NEW_CFA_PATTERN, []byte{0, 2, 0, 2, 0, 1, 1, 2}))
+	// The size written little endian in big endian data.
+	assert.Equal(t, CFAPattern{{CFARed, CFAGreen, CFABlue}},
+		decode(IfdExif, EXIF_TAG_NEW_CFA_PATTERN, []byte{3, 0, 1, 0, 0, 1, 2}))
+	_, err := set(IfdExif, EXIF_TAG_NEW_CFA_PATTERN, []byte{0, 2, 0, 2, 0}).Decode()
+	assert.True(t, errors.Is(err, ErrLengthNotMatch))
+
+	oecf := []byte{0, 2, 0, 2}
+	oecf = append(oecf, "Level\x00Value\x00"...)
+	for _, v := range []int32{1, 2, -1, 2, 3, 1, 1, 4} {
+		oecf = binary.BigEndian.AppendUint32(oecf, uint32(v))
+	}
+	assert.Equal(t, &ResponseTable{
+		Columns: []string{"Level", "Value"},
+		Rows:    [][]float64{{0.5, -0.5}, {3, 0.25}},
+	}, decode(IfdExif, EXIF_TAG_OECF, oecf))
+	_, err = set(IfdExif, EXIF_TAG_SPATIAL_FREQUENCY_RESPONSE, oecf[:20]).Decode()
+	assert.True(t, errors.Is(err, ErrValueTooSmall))
+
+	settings := []byte{0, 2, 0, 1}
+	for _, c := range "Mode\x00Auto\x00" {
+		settings = binary.BigEndian.AppendUint16(settings, uint16(c))
+	}
+	assert.Equal(t, &DeviceSettings{Columns: 2, Rows: 1, Settings: []string{"Mode", "Auto"}},
+		decode(IfdExif, EXIF_TAG_DEVICE_SETTING_DESCRIPTION, settings))
+
+	// Other UNDEFINED values are returned as they are.
+	assert.Equal(t, []byte{1, 2}, decode(IfdExif, EXIF_TAG_MAKER_NOTE, []byte{1, 2}))
+
+	_, err = set(IfdExif, EXIF_TAG_EXIF_VERSION, []byte("02")).ReadAsVersion()
+	assert.True(t, errors.Is(err, ErrValueTooSmall))
+}
+
+func TestGetDecodedValue(t *testing.T) {
+	data, err := Read("_examples/resources/test.jpg")
+	require.NoError(t, err)
+
+	h := NewHelper(data)
+	v, err := h.GetDecodedValue(IfdExif, EXIF_TAG_EXIF_VERSION)
+	require.NoError(t, err)
+	assert.Regexp(t, `^0[0-9]{3}$`, v)
+
+	v, err = h.GetDecodedValue(IfdExif, EXIF_TAG_COMPONENTS_CONFIGURATION)
+	require.NoError(t, err)
+	assert.Equal(t, "YCbCr", v)
+
+	_, err = h.GetDecodedValue(IfdExif, Tag(0x9999))
+	assert.True(t, errors.Is(err, ErrNotFoundEntry))
+}